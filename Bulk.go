@@ -0,0 +1,229 @@
+package koiApi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// BulkOptions configures a bulk operation.
+type BulkOptions struct {
+	Workers     int                 // Concurrent requests; defaults to 4
+	StopOnError bool                // Stop starting new work after the first failure; the rest is reported as skipped
+	DryRun      bool                // Validate and report what would be done without changing anything
+	Progress    func(BulkProgress)  // Called after each object; calls are serialized
+	ProgressCh  chan<- BulkProgress // Receives the same updates as Progress; once ctx is done, only those it has room for. Not closed by the bulk helpers
+}
+
+// BulkProgress reports the state of a bulk operation after one object finished.
+type BulkProgress struct {
+	Done   int    // Objects finished so far, including failures
+	Failed int    // Failures so far
+	Total  int    // Objects in the operation
+	IRI    string // Object that just finished
+	Err    error  // Its error, if any
+}
+
+// BulkResult is the outcome for one object.
+type BulkResult[T KoiObject] struct {
+	Index   int   // Position in the input slice
+	Input   T     // Object as passed in
+	Output  T     // Object returned by the server; the input for dry runs and deletes
+	Err     error // Failure, if any
+	Skipped bool  // Not attempted because an earlier object failed with StopOnError, or ctx ended
+}
+
+// BulkReport collects the results of a bulk operation in input order.
+type BulkReport[T KoiObject] struct {
+	Results   []*BulkResult[T]
+	Succeeded int
+	Failed    int
+	Skipped   int
+	DryRun    bool
+}
+
+// Err joins the errors of every failed object, or returns nil if none failed.
+func (r *BulkReport[T]) Err() error {
+	var errs []error
+	for _, res := range r.Results {
+		if res.Err != nil {
+			errs = append(errs, fmt.Errorf("%d %s: %w", res.Index, res.Input.IRI(), res.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Failures returns the results that failed.
+func (r *BulkReport[T]) Failures() []*BulkResult[T] {
+	var out []*BulkResult[T]
+	for _, res := range r.Results {
+		if res.Err != nil {
+			out = append(out, res)
+		}
+	}
+	return out
+}
+
+// Summary
+func (r *BulkReport[T]) Summary() string {
+	dry := ""
+	if r.DryRun {
+		dry = " (dry run)"
+	}
+	return fmt.Sprintf("%d succeeded, %d failed, %d skipped%s", r.Succeeded, r.Failed, r.Skipped, dry)
+}
+
+// BulkCreate creates objs concurrently.
+func BulkCreate[T KoiObject](ctx context.Context, objs []T, opts BulkOptions) *BulkReport[T] {
+	return runBulk(ctx, objs, opts, func(ctx context.Context, o T) (T, error) {
+		if opts.DryRun {
+			return o, o.Validate()
+		}
+		var out T
+		err := GetClient().createResource(ctx, baseObjPath(o), o, o, &out)
+		return out, err
+	})
+}
+
+// BulkPatch patches objs concurrently.
+func BulkPatch[T KoiObject](ctx context.Context, objs []T, opts BulkOptions) *BulkReport[T] {
+	return runBulk(ctx, objs, opts, func(ctx context.Context, o T) (T, error) {
+		if opts.DryRun {
+			return o, o.Validate()
+		}
+		var out T
+		err := GetClient().patchResourceContext(ctx, o.IRI(), o, &out)
+		return out, err
+	})
+}
+
+// BulkDelete deletes objs concurrently.
+func BulkDelete[T KoiObject](ctx context.Context, objs []T, opts BulkOptions) *BulkReport[T] {
+	return runBulk(ctx, objs, opts, func(ctx context.Context, o T) (T, error) {
+		if opts.DryRun {
+			return o, nil
+		}
+		return o, GetClient().deleteResourceContext(ctx, o.IRI())
+	})
+}
+
+// BulkTag adds tags to items concurrently, keeping the tags each item already has. Each item is re-read
+// before patching, so the inputs only need an ID. Dry runs read but do not patch.
+func BulkTag(ctx context.Context, items []*Item, tags []*Tag, opts BulkOptions) *BulkReport[*Item] {
+	c := GetClient() // Dry runs read the items too
+	return runBulk(ctx, items, opts, func(ctx context.Context, i *Item) (*Item, error) {
+		var current *Item
+		if err := c.getResourceContext(ctx, (&Item{ID: i.ID}).IRI(), &current); err != nil {
+			return i, err
+		}
+		var existing []*Tag
+		if err := c.listResourcesContext(ctx, current.IRI()+"/tags", &existing); err != nil {
+			return i, err
+		}
+		current.Tags = nil
+		have := map[string]bool{}
+		for _, t := range existing {
+			current.Tags = append(current.Tags, t.IRI())
+			have[t.IRI()] = true
+		}
+		changed := false
+		for _, t := range tags {
+			if !have[t.IRI()] {
+				current.Tags = append(current.Tags, t.IRI())
+				have[t.IRI()] = true
+				changed = true
+			}
+		}
+		if !changed || opts.DryRun {
+			return current, nil
+		}
+		var out *Item
+		err := c.patchResourceContext(ctx, current.IRI(), current, &out)
+		return out, err
+	})
+}
+
+// runBulk applies fn to every object through a pool of opts.Workers goroutines. fn gets ctx, so cancelling it
+// also ends requests in flight; StopOnError only stops new work and lets those finish.
+func runBulk[T KoiObject](ctx context.Context, objs []T, opts BulkOptions, fn func(context.Context, T) (T, error)) *BulkReport[T] {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = 4
+	}
+	report := &BulkReport[T]{Results: make([]*BulkResult[T], len(objs)), DryRun: opts.DryRun}
+	for i, o := range objs {
+		report.Results[i] = &BulkResult[T]{Index: i, Input: o, Output: o, Skipped: true}
+	}
+
+	// The shared client is created lazily and without locking, so it is set up before the workers race for it.
+	// Dry runs of the plain operations stay offline.
+	if !opts.DryRun {
+		GetClient()
+	}
+	dispatch, cancel := context.WithCancel(ctx)
+	defer cancel()
+	jobs := make(chan *BulkResult[T])
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for res := range jobs {
+				if dispatch.Err() != nil {
+					continue // Left as skipped
+				}
+				out, err := fn(ctx, res.Input)
+				mu.Lock()
+				res.Skipped = false
+				res.Err = err
+				if err == nil {
+					res.Output = out
+					report.Succeeded++
+				} else {
+					report.Failed++
+					if opts.StopOnError {
+						cancel()
+					}
+				}
+				p := BulkProgress{Done: report.Succeeded + report.Failed, Failed: report.Failed, Total: len(objs), IRI: res.Input.IRI(), Err: err}
+				if opts.Progress != nil {
+					opts.Progress(p)
+				}
+				mu.Unlock()
+				if opts.ProgressCh != nil {
+					select {
+					case opts.ProgressCh <- p:
+					case <-ctx.Done():
+						// The caller may have stopped reading, so the update is only sent if it fits.
+						select {
+						case opts.ProgressCh <- p:
+						default:
+						}
+					}
+				}
+			}
+		}()
+	}
+feed:
+	for _, res := range report.Results {
+		if dispatch.Err() != nil {
+			break
+		}
+		select {
+		case jobs <- res:
+		case <-dispatch.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, res := range report.Results {
+		if res.Skipped {
+			report.Skipped++
+		}
+	}
+	return report
+}
@@ -0,0 +1,441 @@
+package koiApi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// ErrNotCached is returned by CachedClient for paths it does not hold.
+var ErrNotCached = errors.New("not in cache")
+
+// cachedKinds lists the resource types mirrored by CachedClient, keyed like basePathForType.
+// Types whose value is false have no updatedAt and are always fetched in full.
+var cachedKinds = map[string]bool{
+	"album":       true,
+	"choicelist":  true,
+	"collection":  true,
+	"datum":       true,
+	"field":       false,
+	"item":        true,
+	"loan":        false,
+	"photo":       true,
+	"tag":         true,
+	"tagcategory": true,
+	"template":    true,
+	"wish":        true,
+	"wishlist":    true,
+}
+
+// cachedRelations maps a sub-resource such as /api/collections/{id}/items to the kind it lists and the
+// JSON field on that kind which holds the parent IRI.
+var cachedRelations = map[string]struct{ kind, field string }{
+	"collection/children": {"collection", "parent"},
+	"collection/items":    {"item", "collection"},
+	"collection/data":     {"datum", "collection"},
+	"item/data":           {"datum", "item"},
+	"item/loans":          {"loan", "item"},
+	"album/children":      {"album", "parent"},
+	"album/photos":        {"photo", "album"},
+	"wishlist/children":   {"wishlist", "parent"},
+	"wishlist/wishes":     {"wish", "wishlist"},
+	"template/fields":     {"field", "template"},
+	"tagcategory/tags":    {"tag", "category"},
+}
+
+// cacheMeta records the high-water marks used for incremental refreshes.
+type cacheMeta struct {
+	Refreshed time.Time            `json:"refreshed"` // Local time of the last refresh
+	Updated   map[string]time.Time `json:"updated"`   // Latest updatedAt seen per kind
+	Logged    time.Time            `json:"logged"`    // Latest loggedAt seen in /api/logs
+}
+
+// CachedClient serves reads from an on-disk mirror of the server, keyed by IRI. It satisfies Client, so
+// GetFrom and ListFrom work against it offline; Refresh brings it up to date from the live client.
+type CachedClient struct {
+	dir      string
+	live     *koiClient
+	mu       sync.RWMutex
+	store    map[string]map[string]json.RawMessage // kind -> IRI -> object
+	meta     cacheMeta
+	lastPath string
+	lastErr  error
+}
+
+// NewCachedClient opens (or creates) a cache in dir. live is used by Refresh; when nil, GetClient() is used.
+func NewCachedClient(dir string, live *koiClient) (*CachedClient, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating cache directory %s: %w", dir, err)
+	}
+	c := &CachedClient{dir: dir, live: live, store: map[string]map[string]json.RawMessage{}}
+	c.meta.Updated = map[string]time.Time{}
+	if data, err := os.ReadFile(filepath.Join(dir, "meta.json")); err == nil {
+		if err := json.Unmarshal(data, &c.meta); err != nil {
+			return nil, fmt.Errorf("decoding cache metadata: %w", err)
+		}
+		if c.meta.Updated == nil {
+			c.meta.Updated = map[string]time.Time{}
+		}
+	}
+	for kind := range cachedKinds {
+		objs := map[string]json.RawMessage{}
+		data, err := os.ReadFile(filepath.Join(dir, kind+".json"))
+		if err == nil {
+			if err := json.Unmarshal(data, &objs); err != nil {
+				return nil, fmt.Errorf("decoding cached %s: %w", kind, err)
+			}
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("reading cached %s: %w", kind, err)
+		}
+		c.store[kind] = objs
+	}
+	return c, nil
+}
+
+// Refreshed returns when the cache was last refreshed; the zero time means never.
+func (c *CachedClient) Refreshed() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.meta.Refreshed
+}
+
+// Refresh updates the cache from the server. The first refresh fetches everything; later refreshes only
+// fetch objects created or updated since the last one and drop objects that /api/logs reports as deleted.
+// Pass full to refetch everything.
+func (c *CachedClient) Refresh(full ...bool) error {
+	live := c.live
+	if live == nil {
+		live = GetClient()
+	}
+	// The high-water marks are copied under the lock, since lookups and other refreshes may run meanwhile.
+	c.mu.RLock()
+	all := getArg(false, full) || c.meta.Refreshed.IsZero()
+	updated := maps.Clone(c.meta.Updated)
+	logged := c.meta.Logged
+	c.mu.RUnlock()
+	started := time.Now()
+
+	kinds := make([]string, 0, len(cachedKinds))
+	for kind := range cachedKinds {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	fetched := map[string][]json.RawMessage{}
+	replace := map[string]bool{}
+	for _, kind := range kinds {
+		since, ok := updated[kind]
+		incremental := !all && cachedKinds[kind] && ok
+		replace[kind] = !incremental
+		if !incremental {
+			var objs []json.RawMessage
+			if err := live.listResources(basePathForType[kind], &objs); err != nil {
+				return fmt.Errorf("refreshing %s: %w", kind, err)
+			}
+			fetched[kind] = objs
+			continue
+		}
+		// Objects created since the last refresh have no updatedAt yet, so both timestamps are queried.
+		for _, field := range []string{"createdAt", "updatedAt"} {
+			var objs []json.RawMessage
+			if err := live.listResources(basePathForType[kind], &objs, field+"[after]="+since.Format(time.RFC3339)); err != nil {
+				return fmt.Errorf("refreshing %s: %w", kind, err)
+			}
+			fetched[kind] = append(fetched[kind], objs...)
+		}
+	}
+
+	var deleted []*Log
+	if !all {
+		var err error
+		if deleted, err = Deleted(live, logged); err != nil {
+			return fmt.Errorf("reading deletion history: %w", err)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, kind := range kinds {
+		if replace[kind] {
+			c.store[kind] = map[string]json.RawMessage{}
+		}
+		for _, raw := range fetched[kind] {
+			iri := rawIRI(kind, raw)
+			if iri == "" {
+				continue
+			}
+			c.store[kind][iri] = raw
+			if t := rawModifiedAt(raw); t.After(c.meta.Updated[kind]) {
+				c.meta.Updated[kind] = t
+			}
+		}
+	}
+	for _, l := range deleted {
+		if l.LoggedAt.After(c.meta.Logged) {
+			c.meta.Logged = l.LoggedAt
+		}
+		if objs, ok := c.store[l.Kind()]; ok {
+			delete(objs, l.ObjectIRI())
+		}
+	}
+	if all && started.After(c.meta.Logged) {
+		// Deletions before now are already reflected; only later log entries matter.
+		c.meta.Logged = started
+	}
+	if started.After(c.meta.Refreshed) {
+		c.meta.Refreshed = started
+	}
+	return c.save()
+}
+
+// save writes the store to disk; the caller holds the lock.
+func (c *CachedClient) save() error {
+	for kind, objs := range c.store {
+		if err := writeJSONFile(filepath.Join(c.dir, kind+".json"), objs); err != nil {
+			return err
+		}
+	}
+	return writeJSONFile(filepath.Join(c.dir, "meta.json"), c.meta)
+}
+
+// getResource serves a single object, or a single related object such as /api/items/{id}/collection.
+func (c *CachedClient) getResource(path string, out interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastPath, c.lastErr = path, nil
+
+	kind, iri, rel := c.splitPath(path)
+	raw, ok := c.store[kind][iri]
+	if ok && rel != "" {
+		var fields map[string]json.RawMessage
+		var target string
+		if json.Unmarshal(raw, &fields) == nil {
+			json.Unmarshal(fields[snakeToCamel(rel)], &target)
+		}
+		ok = false
+		if target != "" {
+			raw, ok = c.store[kindForPath(target)][target]
+		}
+	}
+	if !ok {
+		c.lastErr = fmt.Errorf("%s: %w", path, ErrNotCached)
+		return c.lastErr
+	}
+	c.lastErr = json.Unmarshal(raw, out)
+	return c.lastErr
+}
+
+// listResources serves a collection path such as /api/items or /api/collections/{id}/items. Query parameters
+// of the form field=value, field[after]=time and field[before]=time filter the result; page is ignored.
+func (c *CachedClient) listResources(path string, out interface{}, queryParams ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastPath, c.lastErr = path, nil
+
+	kind, parent, rel := c.splitPath(path)
+	var filters []string
+	if rel != "" {
+		r, ok := cachedRelations[kind+"/"+rel]
+		if !ok {
+			c.lastErr = fmt.Errorf("%s: %w", path, ErrNotCached)
+			return c.lastErr
+		}
+		kind = r.kind
+		filters = append(filters, r.field+"="+parent)
+	} else if parent != "" {
+		c.lastErr = fmt.Errorf("%s is not a list path", path)
+		return c.lastErr
+	}
+	objs, ok := c.store[kind]
+	if !ok {
+		c.lastErr = fmt.Errorf("%s: %w", path, ErrNotCached)
+		return c.lastErr
+	}
+	filters = append(filters, queryParams...)
+
+	iris := make([]string, 0, len(objs))
+	for iri := range objs {
+		iris = append(iris, iri)
+	}
+	sort.Strings(iris)
+	var buf bytes.Buffer
+	buf.WriteString("[")
+	n := 0
+	for _, iri := range iris {
+		if !matchesFilters(objs[iri], filters) {
+			continue
+		}
+		if n > 0 {
+			buf.WriteString(",")
+		}
+		buf.Write(objs[iri])
+		n++
+	}
+	buf.WriteString("]")
+	c.lastErr = json.Unmarshal(buf.Bytes(), out)
+	return c.lastErr
+}
+
+// splitPath breaks /api/{type}/{id}/{rel} into the cached kind, the object IRI and the relation.
+func (c *CachedClient) splitPath(path string) (kind, iri, rel string) {
+	path = strings.SplitN(path, "?", 2)[0]
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) < 2 {
+		return "", "", ""
+	}
+	kind = kindForPath("/" + parts[0] + "/" + parts[1])
+	if len(parts) >= 3 {
+		iri = "/" + strings.Join(parts[:3], "/")
+	}
+	if len(parts) >= 4 {
+		rel = parts[3]
+	}
+	return kind, iri, rel
+}
+
+// GetResponse describes the last cache lookup.
+func (c *CachedClient) GetResponse() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.lastErr != nil {
+		return fmt.Sprintf("Cache: %s\nError: %v", c.lastPath, c.lastErr)
+	}
+	return fmt.Sprintf("Cache: %s (refreshed %s)", c.lastPath, c.meta.Refreshed.Format(time.RFC3339))
+}
+
+// PrintError prints the last cache lookup.
+func (c *CachedClient) PrintError() {
+	fmt.Println(c.GetResponse())
+}
+
+// matchesFilters applies field=value, field[after]=t and field[before]=t filters to a raw object.
+func matchesFilters(raw json.RawMessage, filters []string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return false
+	}
+	for _, f := range filters {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok || key == "page" || key == "" {
+			continue
+		}
+		name, op, _ := strings.Cut(strings.TrimSuffix(key, "]"), "[")
+		actual := fmt.Sprint(fields[name])
+		if fields[name] == nil {
+			actual = ""
+		}
+		switch op {
+		case "after", "strictly_after", "before", "strictly_before":
+			have, err1 := time.Parse(time.RFC3339, actual)
+			want, err2 := time.Parse(time.RFC3339, value)
+			if err1 != nil || err2 != nil {
+				return false
+			}
+			switch op {
+			case "after":
+				if have.Before(want) {
+					return false
+				}
+			case "strictly_after":
+				if !have.After(want) {
+					return false
+				}
+			case "before":
+				if have.After(want) {
+					return false
+				}
+			case "strictly_before":
+				if !have.Before(want) {
+					return false
+				}
+			}
+		default:
+			if !strings.EqualFold(actual, value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// rawIRI returns the @id of a raw object, or builds it from the id field.
+func rawIRI(kind string, raw json.RawMessage) string {
+	var ids struct {
+		IRI string `json:"@id"`
+		ID  string `json:"id"`
+	}
+	if err := json.Unmarshal(raw, &ids); err != nil {
+		return ""
+	}
+	if ids.IRI != "" {
+		return ids.IRI
+	}
+	if ids.ID != "" {
+		return basePathForType[kind] + "/" + ids.ID
+	}
+	return ""
+}
+
+func rawModifiedAt(raw json.RawMessage) time.Time {
+	var ts struct {
+		CreatedAt time.Time `json:"createdAt"`
+		UpdatedAt time.Time `json:"updatedAt"`
+	}
+	json.Unmarshal(raw, &ts)
+	if !ts.UpdatedAt.IsZero() {
+		return ts.UpdatedAt
+	}
+	return ts.CreatedAt
+}
+
+// kindForPath returns the basePathForType key for an IRI or base path.
+func kindForPath(path string) string {
+	for kind, base := range basePathForType {
+		if path == base || strings.HasPrefix(path, base+"/") {
+			return kind
+		}
+	}
+	return ""
+}
+
+// kindForClass maps a server class such as App\Entity\TagCategory to its basePathForType key.
+func kindForClass(class string) string {
+	return strings.ToLower(class[strings.LastIndex(class, `\`)+1:])
+}
+
+// snakeToCamel turns items_default_template into itemsDefaultTemplate.
+func snakeToCamel(s string) string {
+	parts := strings.Split(s, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			r := []rune(parts[i])
+			r[0] = unicode.ToUpper(r[0])
+			parts[i] = string(r)
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+func writeJSONFile(filename string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", filename, err)
+	}
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("writing %s: %w", filename, err)
+	}
+	return os.Rename(tmp, filename)
+}
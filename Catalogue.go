@@ -0,0 +1,445 @@
+package koiApi
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CatalogueOptions configures GenerateCatalogue.
+type CatalogueOptions struct {
+	Title      string     // Site title; defaults to the root collection's title
+	ThemeDir   string     // *.html files here replace the built-in templates of the same name; other files are copied to the output
+	ImageSize  ImageSize  // Size of downloaded images; defaults to ImageSizeSmall
+	SkipImages bool       // Link to no images instead of downloading them
+	Client     *koiClient // Server to read the collections and download the images from; defaults to GetClient()
+}
+
+// CatalogueStats reports what GenerateCatalogue wrote.
+type CatalogueStats struct {
+	Collections int
+	Items       int
+	Tags        int
+	Images      int
+	ImageErrors []error // Images that could not be downloaded; their pages are rendered without them
+}
+
+// CatalogueCollection is a collection as seen by the templates.
+type CatalogueCollection struct {
+	*Collection
+	URL      string // Page path relative to the site root
+	Image    string // Local image path relative to the site root, or ""
+	Parent   *CatalogueCollection
+	Children []*CatalogueCollection // Sorted by Title
+	Items    []*CatalogueItem       // Sorted by Name
+}
+
+// CatalogueItem is an item as seen by the templates.
+type CatalogueItem struct {
+	*Item
+	URL        string
+	Image      string
+	Collection *CatalogueCollection
+	Data       []*CatalogueDatum // Ordered by Position
+	Tags       []*CatalogueTag   // Sorted by Label
+}
+
+// CatalogueDatum is a datum as seen by the templates.
+type CatalogueDatum struct {
+	*Datum
+	Image string // Local image of image and sign data
+}
+
+// CatalogueTag is a tag as seen by the templates.
+type CatalogueTag struct {
+	*Tag
+	URL   string
+	Image string
+	Items []*CatalogueItem // Sorted by Name
+}
+
+// CataloguePage is the data every template is executed with.
+type CataloguePage struct {
+	Site       string                 // Site title
+	Root       string                 // Prefix turning site-relative paths into page-relative ones, e.g. "../"
+	Title      string                 // Page title
+	Generated  time.Time              // When the site was generated
+	Home       *CatalogueCollection   // Root collection of the site
+	Collection *CatalogueCollection   // Set on collection pages, and to Home on the index
+	Item       *CatalogueItem         // Set on item pages
+	Tag        *CatalogueTag          // Set on tag pages
+	Tags       []*CatalogueTag        // Every published tag, sorted by Label
+	Breadcrumb []*CatalogueCollection // Ancestors of the page's collection, from Home down
+}
+
+// GenerateCatalogue renders root and its descendants as a static HTML site in outDir: index.html, a page per
+// collection, item and tag, style.css and the downloaded images. Only objects whose FinalVisibility is public
+// are published, and a collection that is not public hides its whole subtree. The pages link to each other
+// with relative paths so the directory can be served or browsed as-is.
+func GenerateCatalogue(ctx context.Context, root *Collection, outDir string, opts CatalogueOptions) (*CatalogueStats, error) {
+	if opts.Client == nil {
+		opts.Client = GetClient()
+	}
+	if opts.ImageSize == "" {
+		opts.ImageSize = ImageSizeSmall
+	}
+	tmpl, err := catalogueTemplates(opts.ThemeDir)
+	if err != nil {
+		return nil, err
+	}
+	var fetched *Collection
+	if err := opts.Client.getResource(root.IRI(), &fetched); err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", root.IRI(), err)
+	}
+	root = fetched
+	if root.FinalVisibility != VisibilityPublic {
+		return nil, fmt.Errorf("%s is %s, not public: %w", root.IRI(), root.FinalVisibility, ErrInvalidInput)
+	}
+	for _, dir := range []string{"collections", "items", "tags", "images"} {
+		if err := os.MkdirAll(filepath.Join(outDir, dir), 0o755); err != nil {
+			return nil, err
+		}
+	}
+
+	g := &catalogue{ctx: ctx, opts: opts, outDir: outDir, stats: &CatalogueStats{}, tags: map[string]*CatalogueTag{}, seen: map[string]bool{}}
+	home, err := g.collection(root, nil)
+	if err != nil {
+		return g.stats, err
+	}
+	tags := make([]*CatalogueTag, 0, len(g.tags))
+	for _, t := range g.tags {
+		sortCatalogueItems(t.Items)
+		tags = append(tags, t)
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Label < tags[j].Label })
+	g.stats.Tags = len(tags)
+
+	site := opts.Title
+	if site == "" {
+		site = root.Title
+	}
+	page := func(title string) CataloguePage {
+		return CataloguePage{Site: site, Root: "../", Title: title, Generated: time.Now(), Home: home, Tags: tags}
+	}
+
+	index := page(site)
+	index.Root, index.Collection = "", home
+	if err := g.render(tmpl, "index.html", "index.html", index); err != nil {
+		return g.stats, err
+	}
+	var pages []func() error
+	var walk func(c *CatalogueCollection, crumbs []*CatalogueCollection)
+	walk = func(c *CatalogueCollection, crumbs []*CatalogueCollection) {
+		p := page(c.Title)
+		p.Collection, p.Breadcrumb = c, crumbs
+		pages = append(pages, func() error { return g.render(tmpl, "collection.html", c.URL, p) })
+		crumbs = append(crumbs[:len(crumbs):len(crumbs)], c)
+		for _, item := range c.Items {
+			p := page(item.Name)
+			p.Collection, p.Item, p.Breadcrumb = c, item, crumbs
+			pages = append(pages, func() error { return g.render(tmpl, "item.html", item.URL, p) })
+		}
+		for _, child := range c.Children {
+			walk(child, crumbs)
+		}
+	}
+	walk(home, nil)
+	for _, t := range tags {
+		p := page(t.Label)
+		p.Tag = t
+		pages = append(pages, func() error { return g.render(tmpl, "tag.html", t.URL, p) })
+	}
+	for _, render := range pages {
+		if err := ctx.Err(); err != nil {
+			return g.stats, err
+		}
+		if err := render(); err != nil {
+			return g.stats, err
+		}
+	}
+	if err := os.WriteFile(filepath.Join(outDir, "style.css"), []byte(catalogueStyle), 0o644); err != nil {
+		return g.stats, err
+	}
+	return g.stats, copyThemeAssets(opts.ThemeDir, outDir)
+}
+
+type catalogue struct {
+	ctx    context.Context
+	opts   CatalogueOptions
+	outDir string
+	stats  *CatalogueStats
+	tags   map[string]*CatalogueTag // Keyed by tag IRI
+	seen   map[string]bool          // Collections converted so far, against parent cycles
+}
+
+// collection converts a public collection and its public contents, returning nil for anything else. Everything
+// is fetched through opts.Client, so the pages and the images come from the same server.
+func (g *catalogue) collection(col *Collection, parent *CatalogueCollection) (*CatalogueCollection, error) {
+	if col.FinalVisibility != VisibilityPublic || g.seen[col.IRI()] {
+		return nil, nil
+	}
+	g.seen[col.IRI()] = true
+	c := &CatalogueCollection{Collection: col, URL: "collections/" + string(col.ID) + ".html", Parent: parent}
+	c.Image = g.image(col)
+	g.stats.Collections++
+
+	var items []*Item
+	if err := g.opts.Client.listResources(col.IRI()+"/items", &items); err != nil {
+		return nil, fmt.Errorf("listing items of %s: %w", col.IRI(), err)
+	}
+	for _, item := range items {
+		if err := g.ctx.Err(); err != nil {
+			return nil, err
+		}
+		if item.FinalVisibility != VisibilityPublic {
+			continue
+		}
+		ci, err := g.item(item, c)
+		if err != nil {
+			return nil, err
+		}
+		c.Items = append(c.Items, ci)
+	}
+	sortCatalogueItems(c.Items)
+
+	var children []*Collection
+	if err := g.opts.Client.listResources(col.IRI()+"/children", &children); err != nil {
+		return nil, fmt.Errorf("listing children of %s: %w", col.IRI(), err)
+	}
+	for _, child := range children {
+		if err := g.ctx.Err(); err != nil {
+			return nil, err
+		}
+		cc, err := g.collection(child, c)
+		if err != nil {
+			return nil, err
+		}
+		if cc != nil {
+			c.Children = append(c.Children, cc)
+		}
+	}
+	sort.Slice(c.Children, func(i, j int) bool { return c.Children[i].Title < c.Children[j].Title })
+	return c, nil
+}
+
+func (g *catalogue) item(item *Item, c *CatalogueCollection) (*CatalogueItem, error) {
+	ci := &CatalogueItem{Item: item, URL: "items/" + string(item.ID) + ".html", Collection: c, Image: g.image(item)}
+	g.stats.Items++
+
+	var data []*Datum
+	if err := g.opts.Client.listResources(item.IRI()+"/data", &data); err != nil {
+		return nil, fmt.Errorf("listing data of %s: %w", item.IRI(), err)
+	}
+	sort.SliceStable(data, func(i, j int) bool { return data[i].Position < data[j].Position })
+	for _, d := range data {
+		if d.FinalVisibility != VisibilityPublic {
+			continue
+		}
+		cd := &CatalogueDatum{Datum: d}
+		if d.DatumType == DatumTypeImage || d.DatumType == DatumTypeSign {
+			cd.Image = g.image(d)
+		}
+		ci.Data = append(ci.Data, cd)
+	}
+
+	var tags []*Tag
+	if err := g.opts.Client.listResources(item.IRI()+"/tags", &tags); err != nil {
+		return nil, fmt.Errorf("listing tags of %s: %w", item.IRI(), err)
+	}
+	for _, t := range tags {
+		if t.Visibility != VisibilityPublic {
+			continue
+		}
+		ct, ok := g.tags[t.IRI()]
+		if !ok {
+			ct = &CatalogueTag{Tag: t, URL: "tags/" + string(t.ID) + ".html", Image: g.image(t)}
+			g.tags[t.IRI()] = ct
+		}
+		ct.Items = append(ct.Items, ci)
+		ci.Tags = append(ci.Tags, ct)
+	}
+	sort.Slice(ci.Tags, func(i, j int) bool { return ci.Tags[i].Label < ci.Tags[j].Label })
+	return ci, nil
+}
+
+// image downloads obj's image into the images directory and returns its site-relative path, or "".
+func (g *catalogue) image(obj KoiObject) string {
+	if g.opts.SkipImages || imageURLFor(obj, g.opts.ImageSize) == "" {
+		return ""
+	}
+	filename, err := DownloadImage(g.ctx, g.opts.Client, obj, g.opts.ImageSize, filepath.Join(g.outDir, "images"))
+	if err != nil {
+		g.stats.ImageErrors = append(g.stats.ImageErrors, fmt.Errorf("%s: %w", obj.IRI(), err))
+		return ""
+	}
+	g.stats.Images++
+	return path.Join("images", filepath.Base(filename))
+}
+
+func (g *catalogue) render(tmpl *template.Template, name, rel string, page CataloguePage) error {
+	f, err := os.Create(filepath.Join(g.outDir, filepath.FromSlash(rel)))
+	if err != nil {
+		return err
+	}
+	if err := tmpl.ExecuteTemplate(f, name, page); err != nil {
+		f.Close()
+		return fmt.Errorf("rendering %s: %w", rel, err)
+	}
+	return f.Close()
+}
+
+func sortCatalogueItems(items []*CatalogueItem) {
+	sort.Slice(items, func(i, j int) bool { return strings.ToLower(items[i].Name) < strings.ToLower(items[j].Name) })
+}
+
+// catalogueTemplates parses the built-in templates, then the theme's *.html files, which replace built-in
+// templates of the same file name.
+func catalogueTemplates(themeDir string) (*template.Template, error) {
+	tmpl := template.New("catalogue").Funcs(template.FuncMap{"dict": catalogueDict})
+	for name, text := range catalogueTemplateText {
+		if _, err := tmpl.New(name).Parse(text); err != nil {
+			return nil, fmt.Errorf("parsing built-in template %s: %w", name, err)
+		}
+	}
+	if themeDir == "" {
+		return tmpl, nil
+	}
+	files, err := filepath.Glob(filepath.Join(themeDir, "*.html"))
+	if err != nil || len(files) == 0 {
+		return tmpl, err
+	}
+	if _, err := tmpl.ParseFiles(files...); err != nil {
+		return nil, fmt.Errorf("parsing theme %s: %w", themeDir, err)
+	}
+	return tmpl, nil
+}
+
+// copyThemeAssets copies the theme's files other than templates, such as a style.css, into the output.
+func copyThemeAssets(themeDir, outDir string) error {
+	if themeDir == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(themeDir)
+	if err != nil {
+		return fmt.Errorf("reading theme %s: %w", themeDir, err)
+	}
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) == ".html" {
+			continue
+		}
+		if err := copyFile(filepath.Join(themeDir, e.Name()), filepath.Join(outDir, e.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// catalogueDict builds a map from alternating keys and values, for passing several values to a template.
+func catalogueDict(kv ...any) (map[string]any, error) {
+	if len(kv)%2 != 0 {
+		return nil, fmt.Errorf("dict needs key/value pairs")
+	}
+	m := make(map[string]any, len(kv)/2)
+	for i := 0; i < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict key %v is not a string", kv[i])
+		}
+		m[key] = kv[i+1]
+	}
+	return m, nil
+}
+
+// catalogueTemplateText holds the built-in theme. Pages include "header.html" and "footer.html"; "children.html" and
+// "items.html" take a dict with Root and Children or Items.
+var catalogueTemplateText = map[string]string{
+	"header.html": `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{if ne .Title .Site}}{{.Title}} · {{end}}{{.Site}}</title>
+<link rel="stylesheet" href="{{.Root}}style.css">
+</head>
+<body>
+<header><a href="{{.Root}}index.html">{{.Site}}</a></header>
+{{with .Breadcrumb}}<nav class="breadcrumb">{{range .}}<a href="{{$.Root}}{{.URL}}">{{.Title}}</a> › {{end}}</nav>{{end}}
+<main>
+`,
+	"footer.html": `</main>
+<footer>Generated {{.Generated.Format "2006-01-02"}}</footer>
+</body>
+</html>
+`,
+	"children.html": `{{$root := .Root}}{{with .Children}}<ul class="collections">{{range .}}
+<li><a href="{{$root}}{{.URL}}">{{if .Image}}<img src="{{$root}}{{.Image}}" alt="">{{end}}<span>{{.Title}}</span></a></li>{{end}}
+</ul>{{end}}`,
+	"items.html": `{{$root := .Root}}{{with .Items}}<ul class="items">{{range .}}
+<li><a href="{{$root}}{{.URL}}">{{if .Image}}<img src="{{$root}}{{.Image}}" alt="">{{end}}<span>{{.Name}}</span></a></li>{{end}}
+</ul>{{end}}`,
+	"index.html": `{{template "header.html" .}}<h1>{{.Site}}</h1>
+{{template "collection-body" .}}
+{{with .Tags}}<h2>Tags</h2>
+<ul class="tags">{{range .}}<li><a href="{{$.Root}}{{.URL}}">{{.Label}}</a> ({{len .Items}})</li>{{end}}</ul>{{end}}
+{{template "footer.html" .}}`,
+	"collection.html": `{{template "header.html" .}}<h1>{{.Collection.Title}}</h1>
+{{template "collection-body" .}}
+{{template "footer.html" .}}`,
+	"collection-body": `{{with .Collection}}{{if .Image}}<img class="cover" src="{{$.Root}}{{.Image}}" alt="">{{end}}
+{{template "children.html" (dict "Root" $.Root "Children" .Children)}}
+{{template "items.html" (dict "Root" $.Root "Items" .Items)}}{{end}}`,
+	"item.html": `{{template "header.html" .}}{{with .Item}}<h1>{{.Name}}</h1>
+{{if .Image}}<img class="cover" src="{{$.Root}}{{.Image}}" alt="">{{end}}
+{{if gt .Quantity 1}}<p>Quantity: {{.Quantity}}</p>{{end}}
+{{with .Data}}<dl class="data">{{range .}}
+{{if eq .DatumType "section"}}<dt class="section">{{.Label}}</dt>{{else if ne .DatumType "blank-line"}}<dt>{{.Label}}</dt>
+<dd>{{if .Image}}<img src="{{$.Root}}{{.Image}}" alt="{{.Label}}">{{else if eq .DatumType "link"}}<a href="{{.Value}}">{{.Value}}</a>{{else}}{{.Value}}{{if .Currency}} {{.Currency}}{{end}}{{end}}</dd>{{end}}{{end}}
+</dl>{{end}}
+{{with .Tags}}<ul class="tags">{{range .}}<li><a href="{{$.Root}}{{.URL}}">{{.Label}}</a></li>{{end}}</ul>{{end}}{{end}}
+{{template "footer.html" .}}`,
+	"tag.html": `{{template "header.html" .}}{{with .Tag}}<h1>{{.Label}}</h1>
+{{if .Image}}<img class="cover" src="{{$.Root}}{{.Image}}" alt="">{{end}}
+{{with .Description}}<p>{{.}}</p>{{end}}
+{{template "items.html" (dict "Root" $.Root "Items" .Items)}}{{end}}
+{{template "footer.html" .}}`,
+}
+
+const catalogueStyle = `body { font-family: system-ui, sans-serif; max-width: 60rem; margin: 0 auto; padding: 1rem; color: #222; }
+header { font-weight: bold; margin-bottom: 1rem; }
+a { color: #2456a4; text-decoration: none; }
+.breadcrumb { font-size: .9rem; margin-bottom: .5rem; }
+.cover { max-width: 20rem; display: block; margin: 1rem 0; }
+ul.collections, ul.items { list-style: none; padding: 0; display: grid; grid-template-columns: repeat(auto-fill, minmax(10rem, 1fr)); gap: 1rem; }
+ul.collections img, ul.items img { width: 100%; aspect-ratio: 1; object-fit: cover; display: block; }
+ul.tags { list-style: none; padding: 0; display: flex; flex-wrap: wrap; gap: .5rem; }
+ul.tags li { background: #eee; border-radius: .25rem; padding: .1rem .5rem; }
+dl.data { display: grid; grid-template-columns: max-content 1fr; gap: .25rem 1rem; }
+dl.data dt { font-weight: bold; }
+dl.data dt.section { grid-column: 1 / -1; margin-top: 1rem; border-bottom: 1px solid #ccc; }
+dl.data img { max-width: 12rem; }
+footer { margin-top: 2rem; font-size: .8rem; color: #777; }
+`
@@ -7,25 +7,25 @@ import (
 
 // Collection represents a collection in Koillection, combining fields for JSON-LD and API interactions.
 type Collection struct {
-	Context              Context    `json:"@context,omitempty" access:"rw"`             // JSON-LD only
-	_ID                  ID         `json:"@id,omitempty" access:"ro"`                  // JSON-LD only
-	Type                 string     `json:"@type,omitempty" access:"rw"`                // JSON-LD only
-	ID                   ID         `json:"id,omitempty" access:"ro"`                   // Identifier
-	Title                string     `json:"title" access:"rw"`                          // Collection title
-	Parent               string     `json:"parent,omitempty" access:"rw"`               // Parent collection IRI
-	Owner                string     `json:"owner,omitempty" access:"ro"`                // Owner IRI
-	Color                string     `json:"color,omitempty" access:"ro"`                // Color code
-	Image                string     `json:"image,omitempty" access:"ro"`                // Image URL
-	SeenCounter          int        `json:"seenCounter,omitempty" access:"ro"`          // View count
-	ItemsDefaultTemplate string     `json:"itemsDefaultTemplate,omitempty" access:"rw"` // Default template IRI
-	Visibility           Visibility `json:"visibility,omitempty" access:"rw"`           // Visibility level
-	ParentVisibility     string     `json:"parentVisibility,omitempty" access:"ro"`     // Parent visibility
-	FinalVisibility      Visibility `json:"finalVisibility,omitempty" access:"ro"`      // Effective visibility
-	ScrapedFromURL       string     `json:"scrapedFromUrl,omitempty" access:"ro"`       // Source URL
-	CreatedAt            time.Time  `json:"createdAt" access:"ro"`                      // Creation timestamp
-	UpdatedAt            time.Time  `json:"updatedAt,omitempty" access:"ro"`            // Update timestamp
-	File                 string     `json:"file,omitempty" access:"wo"`                 // Image file data
-	DeleteImage          bool       `json:"deleteImage,omitempty" access:"wo"`          // Flag to delete image
+	Context              Context             `json:"@context,omitempty" access:"rw"`                         // JSON-LD only
+	_ID                  ID                  `json:"@id,omitempty" access:"ro"`                              // JSON-LD only
+	Type                 string              `json:"@type,omitempty" access:"rw"`                            // JSON-LD only
+	ID                   ID                  `json:"id,omitempty" access:"ro" render:"default"`              // Identifier
+	Title                string              `json:"title" access:"rw" render:"default"`                     // Collection title
+	Parent               string              `json:"parent,omitempty" access:"rw" render:"default"`          // Parent collection IRI
+	Owner                string              `json:"owner,omitempty" access:"ro"`                            // Owner IRI
+	Color                string              `json:"color,omitempty" access:"ro"`                            // Color code
+	Image                string              `json:"image,omitempty" access:"ro"`                            // Image URL
+	SeenCounter          int                 `json:"seenCounter,omitempty" access:"ro"`                      // View count
+	ItemsDefaultTemplate string              `json:"itemsDefaultTemplate,omitempty" access:"rw"`             // Default template IRI
+	Visibility           Visibility          `json:"visibility,omitempty" access:"rw"`                       // Visibility level
+	ParentVisibility     string              `json:"parentVisibility,omitempty" access:"ro"`                 // Parent visibility
+	FinalVisibility      Visibility          `json:"finalVisibility,omitempty" access:"ro" render:"default"` // Effective visibility
+	ScrapedFromURL       string              `json:"scrapedFromUrl,omitempty" access:"ro"`                   // Source URL
+	CreatedAt            time.Time           `json:"createdAt,omitzero" access:"ro"`                         // Creation timestamp
+	UpdatedAt            Nullable[time.Time] `json:"updatedAt,omitzero" access:"ro"`                         // Update timestamp
+	File                 string              `json:"file,omitempty" access:"wo"`                             // Image file data
+	DeleteImage          bool                `json:"deleteImage,omitempty" access:"wo"`                      // Flag to delete image
 }
 
 func (c *Collection) Summary() string {
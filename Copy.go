@@ -0,0 +1,265 @@
+package koiApi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+)
+
+// CopyOptions configures MoveItem, CopyItem and CopyCollection.
+type CopyOptions struct {
+	SkipMedia     bool // Do not re-upload images, files and videos
+	ApplyTemplate bool // Add empty data for the fields of the destination's ItemsDefaultTemplate an item lacks
+	RelatedItems  bool // Link copies to the same related items as the originals
+}
+
+// MoveItem moves item into dest, keeping its data, tags and media.
+func MoveItem(ctx context.Context, item *Item, dest *Collection, opts ...CopyOptions) (*Item, error) {
+	opt := getArg(CopyOptions{}, opts)
+	if err := ctx.Err(); err != nil {
+		return item, err
+	}
+	var moved Item
+	if err := GetClient().patchResource(item.IRI(), map[string]any{"collection": dest.IRI()}, &moved); err != nil {
+		return item, fmt.Errorf("moving %s: %w", item.IRI(), err)
+	}
+	if opt.ApplyTemplate {
+		if err := RunTx("", func(tx *Tx) error { return applyDefaultTemplate(ctx, tx, &moved, dest) }); err != nil {
+			return &moved, err
+		}
+	}
+	return &moved, nil
+}
+
+// CopyItem creates a copy of item in dest with its data (keeping positions), tags and media. If any step
+// fails, everything created so far is deleted again.
+func CopyItem(ctx context.Context, item *Item, dest *Collection, opts CopyOptions) (*Item, error) {
+	var copied *Item
+	err := RunTx("", func(tx *Tx) error {
+		var err error
+		copied, err = copyItem(ctx, tx, item, dest, opts)
+		return err
+	})
+	return copied, err
+}
+
+// CopyCollection deep-copies col with its data, items and child collections below newParent, or as a root
+// collection when newParent is nil. It refuses to copy col below itself. If any step fails, everything created
+// so far is deleted again.
+func CopyCollection(ctx context.Context, col *Collection, newParent *Collection, opts CopyOptions) (*Collection, error) {
+	parent := ""
+	if newParent != nil {
+		ancestors, err := Path(ctx, newParent)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range ancestors {
+			if a.IRI() == col.IRI() {
+				return nil, fmt.Errorf("cannot copy %s below %s: %w", col.IRI(), newParent.IRI(), ErrInvalidInput)
+			}
+		}
+		parent = newParent.IRI()
+	}
+	var copied *Collection
+	err := RunTx("", func(tx *Tx) error {
+		var err error
+		copied, err = copyCollection(ctx, tx, col, parent, opts)
+		return err
+	})
+	return copied, err
+}
+
+func copyCollection(ctx context.Context, tx *Tx, col *Collection, parent string, opts CopyOptions) (*Collection, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	// The contents are listed before the copy exists, so that a copy can never be part of its own listing.
+	data, err := ListData(col)
+	if err != nil {
+		return nil, fmt.Errorf("listing data of %s: %w", col.IRI(), err)
+	}
+	items, err := ListItems(col)
+	if err != nil {
+		return nil, fmt.Errorf("listing items of %s: %w", col.IRI(), err)
+	}
+	children, err := ListChildren(col)
+	if err != nil {
+		return nil, fmt.Errorf("listing children of %s: %w", col.IRI(), err)
+	}
+
+	out := writableCopy(col)
+	out.Parent = parent
+	copied, err := TxCreate(tx, out)
+	if err != nil {
+		return nil, fmt.Errorf("copying %s: %w", col.IRI(), err)
+	}
+	c := GetClient()
+	if !opts.SkipMedia && col.Image != "" {
+		if err := copyMedia(ctx, c, c, col.Image, copied.IRI()+"/image", "fileImage", ""); err != nil {
+			return nil, err
+		}
+	}
+	for _, d := range data {
+		if err := copyDatum(ctx, tx, d, "", copied.IRI(), opts); err != nil {
+			return nil, err
+		}
+	}
+	for _, item := range items {
+		if _, err := copyItem(ctx, tx, item, copied, opts); err != nil {
+			return nil, err
+		}
+	}
+	for _, child := range children {
+		if _, err := copyCollection(ctx, tx, child, copied.IRI(), opts); err != nil {
+			return nil, err
+		}
+	}
+	return copied, nil
+}
+
+func copyItem(ctx context.Context, tx *Tx, item *Item, dest *Collection, opts CopyOptions) (*Item, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	out := writableCopy(item)
+	out.Collection = dest.IRI()
+	out.Tags, out.RelatedItems = nil, nil
+	tags, err := ListTags(item)
+	if err != nil {
+		return nil, fmt.Errorf("listing tags of %s: %w", item.IRI(), err)
+	}
+	for _, t := range tags {
+		out.Tags = append(out.Tags, t.IRI())
+	}
+	if opts.RelatedItems {
+		related, err := ListRelatedItems(item)
+		if err != nil {
+			return nil, fmt.Errorf("listing related items of %s: %w", item.IRI(), err)
+		}
+		for _, r := range related {
+			out.RelatedItems = append(out.RelatedItems, r.IRI())
+		}
+	}
+	copied, err := TxCreate(tx, out)
+	if err != nil {
+		return nil, fmt.Errorf("copying %s: %w", item.IRI(), err)
+	}
+	c := GetClient()
+	if !opts.SkipMedia && item.Image != "" {
+		if err := copyMedia(ctx, c, c, item.Image, copied.IRI()+"/image", "fileImage", ""); err != nil {
+			return nil, err
+		}
+	}
+	data, err := ListData(item)
+	if err != nil {
+		return nil, fmt.Errorf("listing data of %s: %w", item.IRI(), err)
+	}
+	for _, d := range data {
+		if err := copyDatum(ctx, tx, d, copied.IRI(), "", opts); err != nil {
+			return nil, err
+		}
+	}
+	if opts.ApplyTemplate {
+		if err := applyDefaultTemplate(ctx, tx, copied, dest); err != nil {
+			return nil, err
+		}
+	}
+	return copied, nil
+}
+
+// copyDatum copies d onto the given item or collection IRI.
+func copyDatum(ctx context.Context, tx *Tx, d *Datum, item, collection string, opts CopyOptions) error {
+	out := writableCopy(d)
+	out.Item, out.Collection = item, collection
+	copied, err := TxCreate(tx, out)
+	if err != nil {
+		return fmt.Errorf("copying %s: %w", d.IRI(), err)
+	}
+	if opts.SkipMedia {
+		return nil
+	}
+	c := GetClient()
+	return copyDatumMedia(ctx, c, c, d, copied.IRI())
+}
+
+// applyDefaultTemplate adds an empty datum to item for every field of dest's ItemsDefaultTemplate whose name
+// is not already used as a datum label.
+func applyDefaultTemplate(ctx context.Context, tx *Tx, item *Item, dest *Collection) error {
+	if dest.ItemsDefaultTemplate == "" {
+		current, err := Get(&Collection{ID: dest.ID})
+		if err != nil {
+			return fmt.Errorf("fetching %s: %w", dest.IRI(), err)
+		}
+		dest = current
+	}
+	if dest.ItemsDefaultTemplate == "" {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	fields, err := ListFields(&Template{ID: idFromIRI(dest.ItemsDefaultTemplate)})
+	if err != nil {
+		return fmt.Errorf("listing fields of %s: %w", dest.ItemsDefaultTemplate, err)
+	}
+	data, err := ListData(item)
+	if err != nil {
+		return fmt.Errorf("listing data of %s: %w", item.IRI(), err)
+	}
+	have := map[string]bool{}
+	for _, d := range data {
+		have[d.Label] = true
+	}
+	for _, f := range fields {
+		if have[f.Name] {
+			continue
+		}
+		d := &Datum{Item: item.IRI(), Label: f.Name, DatumType: f.FieldType.String(), Position: f.Position, ChoiceList: f.ChoiceList, Visibility: f.Visibility}
+		if _, err := TxCreate(tx, d); err != nil {
+			return fmt.Errorf("adding field %s to %s: %w", f.Name, item.IRI(), err)
+		}
+	}
+	return nil
+}
+
+// copyDatumMedia copies the image, file or video of d from src to the datum dstIRI on dst.
+func copyDatumMedia(ctx context.Context, src, dst *koiClient, d *Datum, dstIRI string) error {
+	switch d.DatumType {
+	case DatumTypeImage, DatumTypeSign:
+		if d.Image != "" {
+			return copyMedia(ctx, src, dst, d.Image, dstIRI+"/image", "fileImage", "")
+		}
+	case DatumTypeFile:
+		if d.File != "" {
+			return copyMedia(ctx, src, dst, d.File, dstIRI+"/file", "fileFile", d.OriginalFilename)
+		}
+	case DatumTypeVideo:
+		if d.Video != "" {
+			return copyMedia(ctx, src, dst, d.Video, dstIRI+"/video", "fileVideo", d.OriginalFilename)
+		}
+	}
+	return nil
+}
+
+// copyMedia streams a media URL from src into an upload to dstPath on dst. An empty filename uses the last
+// element of the URL.
+func copyMedia(ctx context.Context, src, dst *koiClient, srcURL, dstPath, fieldName, filename string) error {
+	if filename == "" {
+		if u, err := url.Parse(srcURL); err == nil {
+			filename = path.Base(u.Path)
+		}
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := src.Download(ctx, srcURL, pw)
+		pw.CloseWithError(err)
+	}()
+	var out map[string]any
+	if err := dst.uploadStream(dstPath, fieldName, pr, UploadOptions{Filename: filename, Context: ctx}, &out); err != nil {
+		pr.CloseWithError(err)
+		return fmt.Errorf("copying %s to %s: %w", srcURL, dstPath, err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package koiApi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// copyServer serves the tree a > b > c, where only c has no children, and logs every request.
+func copyServer(t *testing.T) (requests func() []string) {
+	t.Helper()
+	collections := map[string]string{
+		"a": `{"@id":"/api/collections/a","id":"a","title":"A"}`,
+		"b": `{"@id":"/api/collections/b","id":"b","title":"B","parent":"/api/collections/a"}`,
+		"c": `{"@id":"/api/collections/c","id":"c","title":"C","parent":"/api/collections/b"}`,
+	}
+	children := map[string]string{"a": "b", "b": "c"}
+	var mu sync.Mutex
+	var log []string
+	created := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		log = append(log, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/ld+json")
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/collections/"), "/")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/collections":
+			created++
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"@id":"/api/collections/new%d","id":"new%d"}`, created, created)
+		case r.Method == http.MethodGet && len(parts) == 1:
+			if body, ok := collections[parts[0]]; ok {
+				io.WriteString(w, body)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodGet && len(parts) == 2:
+			if r.URL.Query().Get("page") != "1" {
+				io.WriteString(w, `[]`)
+				return
+			}
+			if child, ok := children[parts[0]]; ok && parts[1] == "children" {
+				fmt.Fprintf(w, `[%s]`, collections[child])
+				return
+			}
+			io.WriteString(w, `[]`)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	defaultClient = NewKoiClient(srv.URL, time.Second)
+	t.Cleanup(func() { defaultClient = nil })
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), log...)
+	}
+}
+
+func TestCopyCollectionBelowItself(t *testing.T) {
+	requests := copyServer(t)
+	a := &Collection{ID: "a", Title: "A"}
+	for _, dest := range []*Collection{
+		a,
+		{ID: "b", Parent: "/api/collections/a"},
+		{ID: "c", Parent: "/api/collections/b"},
+	} {
+		if _, err := CopyCollection(context.Background(), a, dest, CopyOptions{SkipMedia: true}); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("copying a below %s: %v, want ErrInvalidInput", dest.ID, err)
+		}
+	}
+	for _, r := range requests() {
+		if strings.HasPrefix(r, http.MethodPost) {
+			t.Errorf("created something: %s", r)
+		}
+	}
+}
+
+func TestCopyCollectionListsBeforeCreating(t *testing.T) {
+	requests := copyServer(t)
+	b := &Collection{ID: "b", Title: "B", Parent: "/api/collections/a"}
+	copied, err := CopyCollection(context.Background(), b, &Collection{ID: "a"}, CopyOptions{SkipMedia: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if copied.IRI() != "/api/collections/new1" {
+		t.Errorf("copy = %s", copied.IRI())
+	}
+	var posts int
+	listed := false
+	for _, r := range requests() {
+		switch {
+		case r == "GET /api/collections/b/children":
+			listed = true
+		case r == "POST /api/collections":
+			if posts++; posts == 1 && !listed {
+				t.Error("b was created before its children were listed")
+			}
+		}
+	}
+	if posts != 2 {
+		t.Errorf("created %d collections, want b and c", posts)
+	}
+}
@@ -1,5 +1,11 @@
 package koiApi
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
 // DateFormat represents the date format preference for a user.
 type DateFormat string
 
@@ -15,3 +21,54 @@ const (
 func (df DateFormat) String() string {
 	return string(df)
 }
+
+// phpLayout maps PHP date() format characters to Go layout elements.
+var phpLayout = map[rune]string{
+	'd': "02", 'j': "2", 'D': "Mon", 'l': "Monday",
+	'm': "01", 'n': "1", 'M': "Jan", 'F': "January",
+	'Y': "2006", 'y': "06",
+	'H': "15", 'h': "03", 'g': "3", 'i': "04", 's': "05", 'A': "PM", 'a': "pm",
+	'T': "MST", 'P': "-07:00", 'O': "-0700",
+}
+
+// layoutSample is formatted with each layout to check it: every Go layout element formats it differently from
+// its own text, so a literal that Go would read as an element shows up as changed.
+var layoutSample = time.Date(2001, 11, 30, 9, 58, 49, 123456789, time.FixedZone("XYZ", 3600))
+
+// Layout converts the PHP-style format to a Go time layout, e.g. d/m/Y to 02/01/2006. Characters without a
+// Go equivalent are kept literally, as are characters escaped with a backslash. An empty format gives the
+// default, Y-m-d.
+//
+// Go layouts cannot quote literal text, so a format whose literals Go would read as layout elements, such as
+// the 1 in "d.m.Y 1" or the Jan in "\J\a\n d", cannot be converted and is an error.
+func (df DateFormat) Layout() (string, error) {
+	if df == "" {
+		df = DateFormatYMDDash
+	}
+	var layout, want strings.Builder
+	escaped := false
+	for _, r := range string(df) {
+		switch {
+		case escaped:
+			layout.WriteRune(r)
+			want.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		default:
+			if elem, ok := phpLayout[r]; ok {
+				layout.WriteString(elem)
+				want.WriteString(layoutSample.Format(elem))
+			} else {
+				layout.WriteRune(r)
+				want.WriteRune(r)
+			}
+		}
+	}
+	// Formatting the whole layout must give the elements and literals one by one; otherwise a literal, or two
+	// neighbours together, read as something else.
+	if layoutSample.Format(layout.String()) != want.String() {
+		return "", fmt.Errorf("date format %q has literal text that Go reads as a date element: %w", df, ErrInvalidInput)
+	}
+	return layout.String(), nil
+}
@@ -0,0 +1,49 @@
+package koiApi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDateFormatLayout(t *testing.T) {
+	tests := []struct {
+		format DateFormat
+		want   string
+	}{
+		{"", "2006-01-02"},
+		{DateFormatDMYSlash, "02/01/2006"},
+		{DateFormatMDYDash, "01-02-2006"},
+		{"j. F Y", "2. January 2006"},
+		{"D, d M y", "Mon, 02 Jan 06"},
+		{"H:i:s", "15:04:05"},
+		{"g:i a T", "3:04 pm MST"},
+		{"Y-m-d\\TH:i:sP", "2006-01-02T15:04:05-07:00"},
+		{"d.m.Y \\a\\t H", "02.01.2006 at 15"},
+		{"nj", "12"},
+		{"Y年n月j日", "2006年1月2日"},
+		{"d.m.Y 6", "02.01.2006 6"},
+	}
+	for _, tt := range tests {
+		got, err := tt.format.Layout()
+		if err != nil || got != tt.want {
+			t.Errorf("Layout(%q) = %q, %v; want %q", tt.format, got, err, tt.want)
+		}
+	}
+}
+
+func TestDateFormatLayoutLiterals(t *testing.T) {
+	for _, format := range []DateFormat{
+		"d.m.Y 1",       // 1 is the month
+		"\\2 d",         // 2 is the day, even escaped
+		"d/m/Y 15",      // 15 is the hour
+		"\\J\\a\\n d",   // Jan is the month name
+		"d M\\o\\n",     // Mon is the weekday
+		"H:i \\P\\M",    // PM is the AM/PM marker
+		"Y.0",           // .0 is a fraction of a second
+		"M\\u\\a\\r\\y", // Jan and uary together read as January
+	} {
+		if got, err := format.Layout(); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("Layout(%q) = %q, %v; want ErrInvalidInput", format, got, err)
+		}
+	}
+}
@@ -30,33 +30,33 @@ const (
 
 // Datum represents a custom data field in Koillection, combining fields for JSON-LD and API interactions.
 type Datum struct {
-	Context             Context    `json:"@context,omitempty" access:"rw"`            // JSON-LD only
-	_ID                 ID         `json:"@id,omitempty" access:"ro"`                 // JSON-LD only
-	Type                string     `json:"@type,omitempty" access:"rw"`               // JSON-LD only
-	ID                  ID         `json:"id,omitempty" access:"ro"`                  // Identifier
-	Item                string     `json:"item,omitempty" access:"rw"`                // Item IRI
-	Collection          string     `json:"collection,omitempty" access:"rw"`          // Collection IRI
-	DatumType           string     `json:"type" access:"rw"`                          // Custom data field type
-	Label               string     `json:"label" access:"rw"`                         // Field label
-	Value               string     `json:"value,omitempty" access:"rw"`               // Field value
-	Position            int        `json:"position,omitempty" access:"rw"`            // Field position
-	Currency            string     `json:"currency,omitempty" access:"rw"`            // Currency code
-	Image               string     `json:"image,omitempty" access:"ro"`               // Image URL
-	ImageSmallThumbnail string     `json:"imageSmallThumbnail,omitempty" access:"ro"` // Small thumbnail URL
-	ImageLargeThumbnail string     `json:"imageLargeThumbnail,omitempty" access:"ro"` // Large thumbnail URL
-	File                string     `json:"file,omitempty" access:"ro"`                // File URL
-	Video               string     `json:"video,omitempty" access:"ro"`               // Video URL
-	OriginalFilename    string     `json:"originalFilename,omitempty" access:"ro"`    // Original file name
-	ChoiceList          string     `json:"choiceList,omitempty" access:"rw"`          // Choice list IRI
-	Owner               string     `json:"owner,omitempty" access:"ro"`               // Owner IRI
-	Visibility          Visibility `json:"visibility,omitempty" access:"rw"`          // Visibility level
-	ParentVisibility    string     `json:"parentVisibility,omitempty" access:"ro"`    // Parent visibility
-	FinalVisibility     Visibility `json:"finalVisibility,omitempty" access:"ro"`     // Effective visibility
-	CreatedAt           time.Time  `json:"createdAt" access:"ro"`                     // Creation timestamp
-	UpdatedAt           time.Time  `json:"updatedAt,omitempty" access:"ro"`           // Update timestamp
-	FileImage           string     `json:"fileImage,omitempty" access:"wo"`           // Image file data
-	FileFile            string     `json:"fileFile,omitempty" access:"wo"`            // File data
-	FileVideo           string     `json:"fileVideo,omitempty" access:"wo"`           // Video file data
+	Context             Context             `json:"@context,omitempty" access:"rw"`                  // JSON-LD only
+	_ID                 ID                  `json:"@id,omitempty" access:"ro"`                       // JSON-LD only
+	Type                string              `json:"@type,omitempty" access:"rw"`                     // JSON-LD only
+	ID                  ID                  `json:"id,omitempty" access:"ro" render:"default"`       // Identifier
+	Item                string              `json:"item,omitempty" access:"rw"`                      // Item IRI
+	Collection          string              `json:"collection,omitempty" access:"rw"`                // Collection IRI
+	DatumType           string              `json:"type" access:"rw" render:"default"`               // Custom data field type
+	Label               string              `json:"label" access:"rw" render:"default"`              // Field label
+	Value               string              `json:"value,omitempty" access:"rw" render:"default"`    // Field value
+	Position            int                 `json:"position,omitempty" access:"rw" render:"default"` // Field position
+	Currency            string              `json:"currency,omitempty" access:"rw"`                  // Currency code
+	Image               string              `json:"image,omitempty" access:"ro"`                     // Image URL
+	ImageSmallThumbnail string              `json:"imageSmallThumbnail,omitempty" access:"ro"`       // Small thumbnail URL
+	ImageLargeThumbnail string              `json:"imageLargeThumbnail,omitempty" access:"ro"`       // Large thumbnail URL
+	File                string              `json:"file,omitempty" access:"ro"`                      // File URL
+	Video               string              `json:"video,omitempty" access:"ro"`                     // Video URL
+	OriginalFilename    string              `json:"originalFilename,omitempty" access:"ro"`          // Original file name
+	ChoiceList          string              `json:"choiceList,omitempty" access:"rw"`                // Choice list IRI
+	Owner               string              `json:"owner,omitempty" access:"ro"`                     // Owner IRI
+	Visibility          Visibility          `json:"visibility,omitempty" access:"rw"`                // Visibility level
+	ParentVisibility    string              `json:"parentVisibility,omitempty" access:"ro"`          // Parent visibility
+	FinalVisibility     Visibility          `json:"finalVisibility,omitempty" access:"ro"`           // Effective visibility
+	CreatedAt           time.Time           `json:"createdAt,omitzero" access:"ro"`                  // Creation timestamp
+	UpdatedAt           Nullable[time.Time] `json:"updatedAt,omitzero" access:"ro"`                  // Update timestamp
+	FileImage           string              `json:"fileImage,omitempty" access:"wo"`                 // Image file data
+	FileFile            string              `json:"fileFile,omitempty" access:"wo"`                  // File data
+	FileVideo           string              `json:"fileVideo,omitempty" access:"wo"`                 // Video file data
 }
 
 func DatumLabelValueMap(data []*Datum) map[string]string {
@@ -0,0 +1,216 @@
+package koiApi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ImageSize selects which rendition of an image to download.
+type ImageSize string
+
+const (
+	ImageSizeOriginal ImageSize = "original"
+	ImageSizeLarge    ImageSize = "large" // ImageLargeThumbnail, falling back to the original
+	ImageSizeSmall    ImageSize = "small" // ImageSmallThumbnail, falling back to the original
+)
+
+// DownloadResult describes a completed or skipped download.
+type DownloadResult struct {
+	NotModified  bool      // The server answered 304; nothing was written
+	Bytes        int64     // Bytes written
+	ContentType  string    // Content-Type of the response
+	LastModified time.Time // Last-Modified of the response, zero if absent
+	ETag         string    // ETag of the response
+}
+
+// Download streams the media at rawURL (absolute, or relative to the server such as /uploads/...) to w.
+func (c *koiClient) Download(ctx context.Context, rawURL string, w io.Writer) (int64, error) {
+	res, err := c.DownloadIfModified(ctx, rawURL, w, time.Time{}, "")
+	return res.Bytes, err
+}
+
+// DownloadIfModified is Download with a conditional GET: when since or etag is set and the server reports the
+// media unchanged, nothing is written and the result has NotModified set.
+func (c *koiClient) DownloadIfModified(ctx context.Context, rawURL string, w io.Writer, since time.Time, etag string) (*DownloadResult, error) {
+	res := &DownloadResult{}
+	target, err := c.resolveURL(rawURL)
+	if err != nil {
+		return res, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return res, fmt.Errorf("creating request: %w", err)
+	}
+	if !since.IsZero() {
+		req.Header.Set("If-Modified-Since", since.UTC().Format(http.TimeFormat))
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if base, err := url.Parse(c.baseURL); err == nil && base.Host == target.Host && c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	// Media may take longer than the client timeout allows for ordinary requests; ctx bounds it instead.
+	hc := *c.httpClient
+	hc.Timeout = 0
+	resp, err := hc.Do(req)
+	if err != nil {
+		c.record(req, nil, nil, err, fmt.Sprintf("Request failed: %v", err), nil)
+		return res, fmt.Errorf("sending request: %w", err)
+	}
+	c.record(req, nil, resp, nil, "", nil)
+	defer resp.Body.Close()
+
+	res.ContentType = resp.Header.Get("Content-Type")
+	res.ETag = resp.Header.Get("ETag")
+	if lm, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		res.LastModified = lm
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		res.NotModified = true
+		return res, nil
+	case http.StatusUnauthorized:
+		return res, ErrUnauthorized
+	case http.StatusNotFound:
+		return res, ErrNotFound
+	default:
+		return res, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	res.Bytes, err = io.Copy(w, resp.Body)
+	if err != nil {
+		return res, fmt.Errorf("downloading %s: %w", target, err)
+	}
+	return res, nil
+}
+
+// DownloadToFile downloads rawURL to filename, skipping the transfer when the file already exists and the
+// server reports it unchanged since the file's modification time. The file is written atomically and its
+// modification time set from Last-Modified. It reports whether the file was (re)written.
+func (c *koiClient) DownloadToFile(ctx context.Context, rawURL, filename string) (bool, error) {
+	var since time.Time
+	if info, err := os.Stat(filename); err == nil {
+		since = info.ModTime()
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return false, fmt.Errorf("creating directory for %s: %w", filename, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*")
+	if err != nil {
+		return false, fmt.Errorf("creating %s: %w", filename, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	res, err := c.DownloadIfModified(ctx, rawURL, tmp, since, "")
+	if closeErr := tmp.Close(); err == nil && closeErr != nil {
+		err = closeErr
+	}
+	if err != nil || res.NotModified {
+		return false, err
+	}
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return false, fmt.Errorf("writing %s: %w", filename, err)
+	}
+	if !res.LastModified.IsZero() {
+		os.Chtimes(filename, res.LastModified, res.LastModified)
+	}
+	return true, nil
+}
+
+// resolveURL makes a media URL absolute against the client's base URL.
+func (c *koiClient) resolveURL(rawURL string) (*url.URL, error) {
+	if rawURL == "" {
+		return nil, fmt.Errorf("empty media URL")
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing URL %s: %w", rawURL, err)
+	}
+	if u.IsAbs() {
+		return u, nil
+	}
+	base, err := url.Parse(c.baseURL + "/")
+	if err != nil {
+		return nil, fmt.Errorf("parsing base URL: %w", err)
+	}
+	return base.ResolveReference(&url.URL{Path: strings.TrimPrefix(u.Path, "/"), RawQuery: u.RawQuery}), nil
+}
+
+// DownloadImage downloads obj's image in the given size into dir, named after the file on the server, and
+// returns the local path. Unchanged files are not downloaded again. A nil client means GetClient().
+func DownloadImage[T KoiObject](ctx context.Context, c *koiClient, obj T, size ImageSize, dir string) (string, error) {
+	imageURL := imageURLFor(obj, size)
+	if imageURL == "" {
+		return "", fmt.Errorf("%T %s has no image", obj, obj.GetID())
+	}
+	return downloadInto(ctx, c, imageURL, "", dir)
+}
+
+// DownloadImage downloads the item's image in the given size into dir and returns the local path.
+func (i *Item) DownloadImage(ctx context.Context, c *koiClient, size ImageSize, dir string) (string, error) {
+	return DownloadImage(ctx, c, i, size, dir)
+}
+
+// DownloadImage downloads the datum's image (image and sign data) in the given size into dir.
+func (a *Datum) DownloadImage(ctx context.Context, c *koiClient, size ImageSize, dir string) (string, error) {
+	return DownloadImage(ctx, c, a, size, dir)
+}
+
+// DownloadFile downloads the datum's file into dir, keeping its OriginalFilename, and returns the local path.
+func (a *Datum) DownloadFile(ctx context.Context, c *koiClient, dir string) (string, error) {
+	if a.File == "" {
+		return "", fmt.Errorf("datum %s has no file", a.ID)
+	}
+	return downloadInto(ctx, c, a.File, a.OriginalFilename, dir)
+}
+
+// DownloadVideo downloads the datum's video into dir, keeping its OriginalFilename, and returns the local path.
+func (a *Datum) DownloadVideo(ctx context.Context, c *koiClient, dir string) (string, error) {
+	if a.Video == "" {
+		return "", fmt.Errorf("datum %s has no video", a.ID)
+	}
+	return downloadInto(ctx, c, a.Video, a.OriginalFilename, dir)
+}
+
+func downloadInto(ctx context.Context, c *koiClient, rawURL, name, dir string) (string, error) {
+	if c == nil {
+		c = GetClient()
+	}
+	if name == "" {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return "", fmt.Errorf("parsing URL %s: %w", rawURL, err)
+		}
+		name = path.Base(u.Path)
+	}
+	filename := filepath.Join(dir, filepath.Base(filepath.Clean("/"+name)))
+	_, err := c.DownloadToFile(ctx, rawURL, filename)
+	return filename, err
+}
+
+// imageURLFor returns the URL of obj's image in the given size, falling back to the original image when the
+// thumbnail is missing.
+func imageURLFor(obj KoiObject, size ImageSize) string {
+	v := reflect.ValueOf(obj).Elem()
+	field := map[ImageSize]string{ImageSizeSmall: "ImageSmallThumbnail", ImageSizeLarge: "ImageLargeThumbnail"}[size]
+	for _, name := range []string{field, "Image"} {
+		if name == "" {
+			continue
+		}
+		if f := v.FieldByName(name); f.IsValid() && f.Kind() == reflect.String && f.String() != "" {
+			return f.String()
+		}
+	}
+	return ""
+}
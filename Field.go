@@ -33,17 +33,17 @@ func (ft FieldType) String() string {
 
 // Field represents a template field in Koillection, combining fields for JSON-LD and API interactions.
 type Field struct {
-	Context    Context    `json:"@context,omitempty" access:"rw"`   // JSON-LD only
-	_ID        ID         `json:"@id,omitempty" access:"ro"`        // JSON-LD only
-	Type       string     `json:"@type,omitempty" access:"rw"`      // JSON-LD only
-	ID         ID         `json:"id,omitempty" access:"ro"`         // Identifier
-	Name       string     `json:"name" access:"rw"`                 // Field name
-	Position   int        `json:"position" access:"rw"`             // Field position
-	FieldType  FieldType  `json:"type" access:"rw"`                 // Field type
-	ChoiceList string     `json:"choiceList,omitempty" access:"rw"` // Choice list IRI
-	Template   string     `json:"template" access:"rw"`             // Template IRI
-	Visibility Visibility `json:"visibility,omitempty" access:"rw"` // Visibility level
-	Owner      string     `json:"owner,omitempty" access:"ro"`      // Owner IRI
+	Context    Context    `json:"@context,omitempty" access:"rw"`            // JSON-LD only
+	_ID        ID         `json:"@id,omitempty" access:"ro"`                 // JSON-LD only
+	Type       string     `json:"@type,omitempty" access:"rw"`               // JSON-LD only
+	ID         ID         `json:"id,omitempty" access:"ro" render:"default"` // Identifier
+	Name       string     `json:"name" access:"rw" render:"default"`         // Field name
+	Position   int        `json:"position" access:"rw" render:"default"`     // Field position
+	FieldType  FieldType  `json:"type" access:"rw" render:"default"`         // Field type
+	ChoiceList string     `json:"choiceList,omitempty" access:"rw"`          // Choice list IRI
+	Template   string     `json:"template" access:"rw"`                      // Template IRI
+	Visibility Visibility `json:"visibility,omitempty" access:"rw"`          // Visibility level
+	Owner      string     `json:"owner,omitempty" access:"ro"`               // Owner IRI
 
 }
 
@@ -0,0 +1,110 @@
+package koiApi
+
+import (
+	"context"
+	"fmt"
+)
+
+// FulfilOptions configures FulfilWish.
+type FulfilOptions struct {
+	ArchiveTo    *Wishlist  // Move the wish to this wishlist instead of deleting it
+	KeepWish     bool       // Leave the wish where it is
+	Quantity     int        // Item quantity; defaults to 1
+	Visibility   Visibility // Item visibility; defaults to the wish's
+	PriceLabel   string     // Label of the price datum; defaults to "Price"
+	LinkLabel    string     // Label of the link datum; defaults to "Link"
+	CommentLabel string     // Label of the comment datum; defaults to "Comment"
+}
+
+// FulfilWish turns wish into an item in target: the item is named after the wish, gets its image, and
+// records its Price/Currency, URL and Comment as price, link and textarea data. The wish is then deleted, or
+// archived with ArchiveTo. If building the item fails, whatever was created is deleted and the wish is left
+// untouched; if only removing the wish fails, the item is returned together with the error.
+func FulfilWish(ctx context.Context, wish *Wish, target *Collection, opts FulfilOptions) (*Item, error) {
+	var item *Item
+	err := RunTx("", func(tx *Tx) error {
+		var err error
+		item, err = itemFromWish(ctx, tx, wish, target, opts)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fulfilling %s: %w", wish.IRI(), err)
+	}
+
+	switch {
+	case opts.KeepWish:
+	case opts.ArchiveTo != nil:
+		var archived Wish
+		if err := GetClient().patchResource(wish.IRI(), map[string]any{"wishlist": opts.ArchiveTo.IRI()}, &archived); err != nil {
+			return item, fmt.Errorf("created %s but could not archive %s: %w", item.IRI(), wish.IRI(), err)
+		}
+	default:
+		if err := Delete(wish); err != nil {
+			return item, fmt.Errorf("created %s but could not delete %s: %w", item.IRI(), wish.IRI(), err)
+		}
+	}
+	return item, nil
+}
+
+func itemFromWish(ctx context.Context, tx *Tx, wish *Wish, target *Collection, opts FulfilOptions) (*Item, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	item := &Item{
+		Name:       wish.Name,
+		Collection: target.IRI(),
+		Quantity:   opts.Quantity,
+		Visibility: wish.Visibility,
+	}
+	if item.Quantity < 1 {
+		item.Quantity = 1
+	}
+	if opts.Visibility != "" {
+		item.Visibility = opts.Visibility
+	}
+	if err := item.Validate(); err != nil {
+		return nil, err
+	}
+	item, err := TxCreate(tx, item)
+	if err != nil {
+		return nil, err
+	}
+	if wish.Image != "" {
+		c := GetClient()
+		if err := copyMedia(ctx, c, c, wish.Image, item.IRI()+"/image", "fileImage", ""); err != nil {
+			return nil, err
+		}
+	}
+
+	var data []*Datum
+	if wish.Price != "" {
+		data = append(data, &Datum{Label: labelOr(opts.PriceLabel, "Price"), DatumType: DatumTypePrice, Value: wish.Price, Currency: wish.Currency})
+	}
+	if wish.URL != "" {
+		data = append(data, &Datum{Label: labelOr(opts.LinkLabel, "Link"), DatumType: DatumTypeLink, Value: wish.URL})
+	}
+	if wish.Comment != "" {
+		data = append(data, &Datum{Label: labelOr(opts.CommentLabel, "Comment"), DatumType: DatumTypeTextarea, Value: wish.Comment})
+	}
+	for i, d := range data {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		d.Item = item.IRI()
+		d.Position = i + 1
+		if err := d.Validate(); err != nil {
+			return nil, err
+		}
+		if _, err := TxCreate(tx, d); err != nil {
+			return nil, fmt.Errorf("adding %s: %w", d.Label, err)
+		}
+	}
+	return item, nil
+}
+
+func labelOr(label, def string) string {
+	if label == "" {
+		return def
+	}
+	return label
+}
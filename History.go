@@ -0,0 +1,167 @@
+package koiApi
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Log types recorded by the server.
+const (
+	LogTypeCreate = "create"
+	LogTypeUpdate = "update"
+	LogTypeDelete = "delete"
+)
+
+// LogFilter selects log entries. Empty fields match everything.
+type LogFilter struct {
+	Classes  []string  // Object classes, short (Item) or qualified (App\Entity\Item)
+	ObjectID string    // A single object's ID
+	Types    []string  // LogTypeCreate, LogTypeUpdate, LogTypeDelete
+	Since    time.Time // Only entries logged after this time
+	Until    time.Time // Only entries logged before this time
+}
+
+// query returns the server-side filters; multi-valued filters are applied client-side by match.
+func (f LogFilter) query() []string {
+	var q []string
+	if len(f.Types) == 1 {
+		q = append(q, "type="+f.Types[0])
+	}
+	if f.ObjectID != "" {
+		q = append(q, "objectId="+f.ObjectID)
+	}
+	if !f.Since.IsZero() {
+		q = append(q, "loggedAt[after]="+f.Since.Format(time.RFC3339))
+	}
+	if !f.Until.IsZero() {
+		q = append(q, "loggedAt[before]="+f.Until.Format(time.RFC3339))
+	}
+	return q
+}
+
+func (f LogFilter) match(l *Log) bool {
+	if len(f.Classes) > 0 && !slices.ContainsFunc(f.Classes, func(c string) bool { return strings.EqualFold(kindForClass(c), l.Kind()) }) {
+		return false
+	}
+	if f.ObjectID != "" && l.ObjectID != f.ObjectID {
+		return false
+	}
+	if len(f.Types) > 0 && !slices.Contains(f.Types, l.LogType) {
+		return false
+	}
+	if !f.Since.IsZero() && !l.LoggedAt.After(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !l.LoggedAt.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+// Kind returns the basePathForType key of the logged object, e.g. item for App\Entity\Item.
+func (l *Log) Kind() string {
+	return kindForClass(l.ObjectClass)
+}
+
+// ObjectIRI returns the IRI of the logged object, or "" for classes the API does not expose.
+func (l *Log) ObjectIRI() string {
+	base, ok := basePathForType[l.Kind()]
+	if !ok || l.ObjectID == "" {
+		return ""
+	}
+	return base + "/" + l.ObjectID
+}
+
+// Logs lists the log entries matching f through c, oldest first.
+func Logs(c Client, f LogFilter) ([]*Log, error) {
+	var logs []*Log
+	if err := c.listResources(basePathForType["log"], &logs, f.query()...); err != nil {
+		return nil, fmt.Errorf("listing logs: %w", err)
+	}
+	out := logs[:0]
+	for _, l := range logs {
+		if f.match(l) {
+			out = append(out, l)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].LoggedAt.Before(out[j].LoggedAt) })
+	return out, nil
+}
+
+// Timeline returns the history of one object, oldest first.
+func Timeline(c Client, obj KoiObject) ([]*Log, error) {
+	return Logs(c, LogFilter{ObjectID: obj.GetID(), Classes: []string{reflect.TypeOf(obj).Elem().Name()}})
+}
+
+// Deleted returns the deletions logged after since, oldest first. A zero since returns all of them.
+func Deleted(c Client, since time.Time) ([]*Log, error) {
+	return Logs(c, LogFilter{Types: []string{LogTypeDelete}, Since: since})
+}
+
+// ClassChanges groups the changes to one class.
+type ClassChanges struct {
+	Kind    string
+	Created []*Log
+	Updated []*Log
+	Deleted []*Log
+}
+
+// ChangeSummary describes what changed after a point in time, grouped by class.
+type ChangeSummary struct {
+	Since   time.Time
+	ByKind  map[string]*ClassChanges // Keyed by basePathForType key
+	Entries int
+}
+
+// ChangesSince summarises the log entries after since.
+func ChangesSince(c Client, since time.Time) (*ChangeSummary, error) {
+	logs, err := Logs(c, LogFilter{Since: since})
+	if err != nil {
+		return nil, err
+	}
+	s := &ChangeSummary{Since: since, ByKind: map[string]*ClassChanges{}, Entries: len(logs)}
+	for _, l := range logs {
+		cc := s.ByKind[l.Kind()]
+		if cc == nil {
+			cc = &ClassChanges{Kind: l.Kind()}
+			s.ByKind[l.Kind()] = cc
+		}
+		switch l.LogType {
+		case LogTypeCreate:
+			cc.Created = append(cc.Created, l)
+		case LogTypeDelete:
+			cc.Deleted = append(cc.Deleted, l)
+		default:
+			cc.Updated = append(cc.Updated, l)
+		}
+	}
+	return s, nil
+}
+
+// Summary
+func (cc *ClassChanges) Summary() string {
+	return fmt.Sprintf("%-15s %5d created %5d updated %5d deleted", cc.Kind, len(cc.Created), len(cc.Updated), len(cc.Deleted))
+}
+
+// Text renders one line per class followed by the labels of the objects that changed.
+func (s *ChangeSummary) Text() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Changes since %s: %d entries\n", s.Since.Format(time.RFC3339), s.Entries)
+	for _, kind := range sortedKeys(s.ByKind) {
+		cc := s.ByKind[kind]
+		fmt.Fprintf(&sb, "%s\n", cc.Summary())
+		for _, group := range []struct {
+			verb string
+			logs []*Log
+		}{{"+", cc.Created}, {"~", cc.Updated}, {"-", cc.Deleted}} {
+			for _, l := range group.logs {
+				fmt.Fprintf(&sb, "    %s %s %s\n", group.verb, l.LoggedAt.Format(time.DateTime), l.ObjectLabel)
+			}
+		}
+	}
+	return sb.String()
+}
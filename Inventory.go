@@ -15,15 +15,15 @@ type InventoryInterface interface {
 
 // Inventory represents an inventory record in Koillection, combining fields for JSON-LD and API interactions.
 type Inventory struct {
-	Context   Context   `json:"@context,omitempty" access:"rw"`  // JSON-LD only
-	_ID       ID        `json:"@id,omitempty" access:"ro"`       // JSON-LD only
-	Type      string    `json:"@type,omitempty" access:"rw"`     // JSON-LD only
-	ID        ID        `json:"id,omitempty" access:"ro"`        // Identifier
-	Name      string    `json:"name" access:"rw"`                // Inventory name
-	Content   []string  `json:"content" access:"rw"`             // Inventory content
-	Owner     string    `json:"owner,omitempty" access:"ro"`     // Owner IRI
-	CreatedAt time.Time `json:"createdAt" access:"ro"`           // Creation timestamp
-	UpdatedAt time.Time `json:"updatedAt,omitempty" access:"ro"` // Update timestamp
+	Context   Context             `json:"@context,omitempty" access:"rw"`                  // JSON-LD only
+	_ID       ID                  `json:"@id,omitempty" access:"ro"`                       // JSON-LD only
+	Type      string              `json:"@type,omitempty" access:"rw"`                     // JSON-LD only
+	ID        ID                  `json:"id,omitempty" access:"ro" render:"default"`       // Identifier
+	Name      string              `json:"name" access:"rw" render:"default"`               // Inventory name
+	Content   []string            `json:"content" access:"rw"`                             // Inventory content
+	Owner     string              `json:"owner,omitempty" access:"ro"`                     // Owner IRI
+	CreatedAt time.Time           `json:"createdAt,omitzero" access:"ro" render:"default"` // Creation timestamp
+	UpdatedAt Nullable[time.Time] `json:"updatedAt,omitzero" access:"ro"`                  // Update timestamp
 
 }
 
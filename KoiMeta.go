@@ -1,9 +1,13 @@
 package koiApi
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 
@@ -11,18 +15,21 @@ import (
 )
 
 var basePathForType = map[string]string{
-	"album":      "/api/albums",
-	"choicelist": "/api/choice_lists",
-	"collection": "/api/collections",
-	"item":       "/api/items",
-	"datum":      "/api/data",
-	"loan":       "/api/loans",
-	"photo":      "/api/photos",
-	"tag":        "/api/tags",
-	"template":   "/api/templates",
-	"user":       "/api/users",
-	"wish":       "/api/wishes",
-	"wishlist":   "/api/wishlists",
+	"album":       "/api/albums",
+	"choicelist":  "/api/choice_lists",
+	"collection":  "/api/collections",
+	"item":        "/api/items",
+	"datum":       "/api/data",
+	"field":       "/api/fields",
+	"loan":        "/api/loans",
+	"log":         "/api/logs",
+	"photo":       "/api/photos",
+	"tag":         "/api/tags",
+	"tagcategory": "/api/tag_categories",
+	"template":    "/api/templates",
+	"user":        "/api/users",
+	"wish":        "/api/wishes",
+	"wishlist":    "/api/wishlists",
 }
 
 type koiOp struct {
@@ -108,14 +115,20 @@ func KoiPathForOp(obj KoiObject) (*koiOp, error) {
 		retval = koiOp{caller: fn, op: http.MethodPost, path: fmt.Sprintf("%s/%s/file", basePath, obj.GetID())}
 	case "uploadfilefromfile":
 		retval = koiOp{caller: fn, op: http.MethodPost, path: fmt.Sprintf("%s/%s/file", basePath, obj.GetID())}
+	case "uploadfilefromreader":
+		retval = koiOp{caller: fn, op: http.MethodPost, path: fmt.Sprintf("%s/%s/file", basePath, obj.GetID())}
 	case "uploadimage":
 		retval = koiOp{caller: fn, op: http.MethodPost, path: fmt.Sprintf("%s/%s/image", basePath, obj.GetID())}
 	case "uploadimagefromfile":
 		retval = koiOp{caller: fn, op: http.MethodPost, path: fmt.Sprintf("%s/%s/image", basePath, obj.GetID())}
+	case "uploadimagefromreader":
+		retval = koiOp{caller: fn, op: http.MethodPost, path: fmt.Sprintf("%s/%s/image", basePath, obj.GetID())}
 	case "uploadvideo":
 		retval = koiOp{caller: fn, op: http.MethodPost, path: fmt.Sprintf("%s/%s/video", basePath, obj.GetID())}
 	case "uploadvideofromfile":
 		retval = koiOp{caller: fn, op: http.MethodPost, path: fmt.Sprintf("%s/%s/video", basePath, obj.GetID())}
+	case "uploadvideofromreader":
+		retval = koiOp{caller: fn, op: http.MethodPost, path: fmt.Sprintf("%s/%s/video", basePath, obj.GetID())}
 	// Add more cases as needed
 	default:
 		return &koiOp{caller: fn}, fmt.Errorf("unknown operation: %s for type %T", fn, obj)
@@ -136,12 +149,10 @@ func doCreate[T KoiObject](o T) (T, error) {
 	op := result.op
 	path := result.path
 
-	c := GetClient()
-
 	// if op == POST
 	if op == http.MethodPost {
 		var resp T
-		err := c.postResource(path, o, &resp)
+		err := GetClient().createResource(context.Background(), path, o, o, &resp)
 		return resp, err
 	}
 	fmt.Printf("FAILED: %20s %8s %s\n", caller.ThisFunc(), result.op, result.path)
@@ -269,7 +280,7 @@ func doList[T KoiObject](o T, q ...string) (any, error) {
 
 		case "listtags":
 			// Item, TagCategory
-			var objs []*Field
+			var objs []*Tag
 			err := c.listResources(path, &objs, q...)
 			return objs, err
 
@@ -334,6 +345,39 @@ func doUpload[T KoiObject](o T, magic string, file []byte) (T, error) {
 	if err != nil {
 		return o, fmt.Errorf("failed to get operation path: %w", err)
 	}
+	return doUploadOp(result, o, magic, bytes.NewReader(file), UploadOptions{Size: int64(len(file))})
+}
+
+func doUploadStream[T KoiObject](o T, magic string, r io.Reader, opts UploadOptions) (T, error) {
+	result, err := KoiPathForOp(o)
+	if err != nil {
+		return o, fmt.Errorf("failed to get operation path: %w", err)
+	}
+	return doUploadOp(result, o, magic, r, opts)
+}
+
+func doUploadFromFile[T KoiObject](o T, magic string, fname string, opts ...UploadOptions) (T, error) {
+	// Resolve the operation here; KoiPathForOp keys on its caller's caller.
+	result, err := KoiPathForOp(o)
+	if err != nil {
+		return o, fmt.Errorf("failed to get operation path: %w", err)
+	}
+	file, err := os.Open(fname)
+	if err != nil {
+		return o, fmt.Errorf("failed to read file %s: %w", fname, err)
+	}
+	defer file.Close()
+	opt := getArg(UploadOptions{}, opts)
+	if opt.Filename == "" {
+		opt.Filename = filepath.Base(fname)
+	}
+	if info, err := file.Stat(); err == nil && opt.Size == 0 {
+		opt.Size = info.Size()
+	}
+	return doUploadOp(result, o, magic, file, opt)
+}
+
+func doUploadOp[T KoiObject](result *koiOp, o T, magic string, r io.Reader, opts UploadOptions) (T, error) {
 	op := result.op
 	path := result.path
 
@@ -342,17 +386,9 @@ func doUpload[T KoiObject](o T, magic string, file []byte) (T, error) {
 	// if op == POST
 	if op == http.MethodPost {
 		var resp T
-		err := c.uploadFile(path, file, magic, &resp)
+		err := c.uploadStream(path, magic, r, opts, &resp)
 		return resp, err
 	}
 	fmt.Printf("FAILED: %20s %8s %s\n", caller.ThisFunc(), result.op, result.path)
 	return o, fmt.Errorf("operation %s not implemented for type %T in %s", op, o, caller.ThisFunc())
 }
-
-func doUploadFromFile[T KoiObject](o T, magic string, fname string) (T, error) {
-	file, err := os.ReadFile(fname)
-	if err != nil {
-		return o, fmt.Errorf("failed to read file %s: %w", fname, err)
-	}
-	return doUpload(o, magic, file)
-}
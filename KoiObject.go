@@ -1,5 +1,7 @@
 package koiApi
 
+import "io"
+
 func Create[T KoiObject](obj T) (T, error) {
 	resp, err := doCreate(obj)
 	return resp, err
@@ -29,6 +31,13 @@ func GetDefaultTemplate[T KoiObject](obj T) (*Template, error) {
 	return resp.(*Template), err
 }
 
+// GetFrom retrieves obj through the given client, which may be the live client or a CachedClient.
+func GetFrom[T KoiObject](c Client, obj T) (T, error) {
+	var resp T
+	err := c.getResource(obj.IRI(), &resp)
+	return resp, err
+}
+
 func GetItem[T KoiObject](obj T) (*Item, error) {
 	resp, err := doGet(obj)
 	return resp.(*Item), err
@@ -54,6 +63,13 @@ func List[T KoiObject](obj T, q ...string) ([]T, error) {
 	return resp.([]T), err
 }
 
+// ListFrom lists all objects of obj's type through the given client, which may be the live client or a CachedClient.
+func ListFrom[T KoiObject](c Client, obj T, q ...string) ([]T, error) {
+	var resp []T
+	err := c.listResources(baseObjPath(obj), &resp, q...)
+	return resp, err
+}
+
 func ListChildren[T KoiObject](obj T) ([]T, error) {
 	resp, err := doList(obj)
 	return resp.([]T), err
@@ -109,32 +125,50 @@ func Update[T KoiObject](obj T) (T, error) {
 	return resp, err
 }
 
-func UploadFile[T KoiObject](obj T, file []byte) (any, error) {
+func UploadFile[T KoiObject](obj T, file []byte) (T, error) {
 	resp, err := doUpload(obj, "fileFile", file)
 	return resp, err
 }
 
-func UploadFileFromFile[T KoiObject](obj T, filename string) (any, error) {
-	resp, err := doUploadFromFile(obj, "fileFile", filename)
+func UploadFileFromFile[T KoiObject](obj T, filename string, opts ...UploadOptions) (T, error) {
+	resp, err := doUploadFromFile(obj, "fileFile", filename, opts...)
+	return resp, err
+}
+
+// UploadFileFromReader streams a file from r; see UploadOptions for naming, limits and progress.
+func UploadFileFromReader[T KoiObject](obj T, r io.Reader, opts UploadOptions) (T, error) {
+	resp, err := doUploadStream(obj, "fileFile", r, opts)
 	return resp, err
 }
 
-func UploadImage[T KoiObject](obj T, file []byte) (any, error) {
+func UploadImage[T KoiObject](obj T, file []byte) (T, error) {
 	resp, err := doUpload(obj, "fileImage", file)
 	return resp, err
 }
 
-func UploadImageFromFile[T KoiObject](obj T, filename string) (any, error) {
-	resp, err := doUploadFromFile(obj, "fileImage", filename)
+func UploadImageFromFile[T KoiObject](obj T, filename string, opts ...UploadOptions) (T, error) {
+	resp, err := doUploadFromFile(obj, "fileImage", filename, opts...)
+	return resp, err
+}
+
+// UploadImageFromReader streams an image from r; see UploadOptions for naming, limits and progress.
+func UploadImageFromReader[T KoiObject](obj T, r io.Reader, opts UploadOptions) (T, error) {
+	resp, err := doUploadStream(obj, "fileImage", r, opts)
 	return resp, err
 }
 
-func UploadVideo[T KoiObject](obj T, file []byte) (any, error) {
+func UploadVideo[T KoiObject](obj T, file []byte) (T, error) {
 	resp, err := doUpload(obj, "fileVideo", file)
 	return resp, err
 }
 
-func UploadVideoFromFile[T KoiObject](obj T, filename string) (any, error) {
-	resp, err := doUploadFromFile(obj, "fileVideo", filename)
+func UploadVideoFromFile[T KoiObject](obj T, filename string, opts ...UploadOptions) (T, error) {
+	resp, err := doUploadFromFile(obj, "fileVideo", filename, opts...)
+	return resp, err
+}
+
+// UploadVideoFromReader streams a video from r; see UploadOptions for naming, limits and progress.
+func UploadVideoFromReader[T KoiObject](obj T, r io.Reader, opts UploadOptions) (T, error) {
+	resp, err := doUploadStream(obj, "fileVideo", r, opts)
 	return resp, err
 }
@@ -0,0 +1,442 @@
+package koiApi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// LabelFormat is the output format of WriteLabels.
+type LabelFormat string
+
+const (
+	LabelSVG LabelFormat = "svg" // One SVG document, sheets stacked vertically
+	LabelPDF LabelFormat = "pdf" // One PDF page per sheet
+)
+
+// LabelSheet describes the label stock. Lengths are in millimetres.
+type LabelSheet struct {
+	PageWidth   float64 // Defaults to A4, 210
+	PageHeight  float64 // Defaults to A4, 297
+	Columns     int     // Defaults to 3
+	Rows        int     // Defaults to 8
+	MarginTop   float64 // Distance from the top edge to the first row
+	MarginLeft  float64 // Distance from the left edge to the first column
+	GapX        float64 // Space between columns
+	GapY        float64 // Space between rows
+	LabelWidth  float64 // Defaults to filling the page width between the margins
+	LabelHeight float64 // Defaults to filling the page height between the margins
+	Skip        int     // Labels to leave blank at the start of the first sheet, for partly used sheets
+}
+
+// LabelOptions configures Labels and WriteLabels.
+type LabelOptions struct {
+	Sheet   LabelSheet
+	Format  LabelFormat // Defaults to LabelSVG
+	Data    []string    // Labels of the data to print, in order; missing data are left out
+	BaseURL string      // Web address encoded in the QR codes; defaults to the client's server URL
+	Client  *koiClient  // Defaults to GetClient()
+}
+
+// Label is the content of one printed label.
+type Label struct {
+	Item  *Item
+	Name  string
+	Path  string   // Collection path, e.g. Books/Science fiction
+	Lines []string // "Label: value" for each of LabelOptions.Data found on the item
+	URL   string   // Encoded in the QR code
+}
+
+// ItemURL returns the web page of item on the server at baseURL, which is what label QR codes encode.
+func ItemURL(baseURL string, item *Item) string {
+	return strings.TrimSuffix(baseURL, "/") + "/items/" + string(item.ID)
+}
+
+// Labels resolves the collection path and chosen data of each item.
+func Labels(ctx context.Context, items []*Item, opts LabelOptions) ([]*Label, error) {
+	opts = opts.withDefaults()
+	var collections []*Collection
+	if err := opts.Client.listResources(baseObjPath(&Collection{}), &collections); err != nil {
+		return nil, fmt.Errorf("listing collections: %w", err)
+	}
+	paths := collectionPaths(collections)
+	labels := make([]*Label, 0, len(items))
+	for _, item := range items {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		l := &Label{Item: item, Name: item.Name, Path: paths[item.Collection], URL: ItemURL(opts.BaseURL, item)}
+		if len(opts.Data) > 0 {
+			var data []*Datum
+			if err := opts.Client.listResources(item.IRI()+"/data", &data); err != nil {
+				return nil, fmt.Errorf("listing data of %s: %w", item.IRI(), err)
+			}
+			for _, label := range opts.Data {
+				for _, d := range data {
+					if strings.EqualFold(d.Label, label) && d.Value != "" {
+						l.Lines = append(l.Lines, d.Label+": "+d.Value)
+						break
+					}
+				}
+			}
+		}
+		labels = append(labels, l)
+	}
+	return labels, nil
+}
+
+// WriteLabels writes a label sheet for items to w and returns the number of sheets.
+func WriteLabels(ctx context.Context, w io.Writer, items []*Item, opts LabelOptions) (int, error) {
+	opts = opts.withDefaults()
+	labels, err := Labels(ctx, items, opts)
+	if err != nil {
+		return 0, err
+	}
+	return RenderLabels(w, labels, opts.Sheet, opts.Format)
+}
+
+// RenderLabels lays out labels on sheets in the given format and returns the number of sheets.
+func RenderLabels(w io.Writer, labels []*Label, sheet LabelSheet, format LabelFormat) (int, error) {
+	sheet = sheet.withDefaults()
+	if sheet.LabelWidth <= 0 || sheet.LabelHeight <= 0 {
+		return 0, fmt.Errorf("labels do not fit on a %gx%g mm page: %w", sheet.PageWidth, sheet.PageHeight, ErrInvalidInput)
+	}
+	if len(labels) == 0 {
+		// A PDF without pages is rejected by viewers, so neither format writes an empty document.
+		return 0, fmt.Errorf("no labels to render: %w", ErrInvalidInput)
+	}
+	perPage := sheet.Columns * sheet.Rows
+	pages := (sheet.Skip + len(labels) + perPage - 1) / perPage
+	var pw labelPageWriter
+	switch format {
+	case LabelSVG, "":
+		pw = &svgLabels{sheet: sheet, pages: pages}
+	case LabelPDF:
+		pw = &pdfLabels{sheet: sheet}
+	default:
+		return 0, fmt.Errorf("unknown label format %q: %w", format, ErrInvalidInput)
+	}
+	for page := 0; page < pages; page++ {
+		var boxes []labelBox
+		for slot := 0; slot < perPage; slot++ {
+			i := page*perPage + slot - sheet.Skip
+			if i < 0 || i >= len(labels) {
+				continue
+			}
+			col, row := slot%sheet.Columns, slot/sheet.Columns
+			boxes = append(boxes, labelBox{
+				label: labels[i],
+				x:     sheet.MarginLeft + float64(col)*(sheet.LabelWidth+sheet.GapX),
+				y:     sheet.MarginTop + float64(row)*(sheet.LabelHeight+sheet.GapY),
+			})
+		}
+		if err := pw.page(boxes); err != nil {
+			return page, err
+		}
+	}
+	bw := bufio.NewWriter(w)
+	if err := pw.finish(bw); err != nil {
+		return pages, err
+	}
+	return pages, bw.Flush()
+}
+
+// ItemFromQR resolves a scanned QR payload back to its item. The payload may be an item URL as printed by
+// WriteLabels, an item IRI or a bare item ID. URLs pointing to another server are rejected.
+func ItemFromQR(ctx context.Context, payload string) (*Item, error) {
+	return itemFromQR(ctx, GetClient(), payload)
+}
+
+func itemFromQR(ctx context.Context, c *koiClient, payload string) (*Item, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	payload = strings.TrimSpace(payload)
+	u, err := url.Parse(payload)
+	if err != nil {
+		return nil, fmt.Errorf("QR payload %q: %w", payload, ErrInvalidInput)
+	}
+	if u.Host != "" {
+		base, err := url.Parse(c.baseURL)
+		if err != nil || !strings.EqualFold(u.Host, base.Host) {
+			return nil, fmt.Errorf("QR payload %q is not from %s: %w", payload, c.baseURL, ErrInvalidInput)
+		}
+	}
+	id := u.Path
+	if i := strings.LastIndex(id, "/items/"); i >= 0 {
+		id = id[i+len("/items/"):]
+	} else if strings.Contains(id, "/") {
+		return nil, fmt.Errorf("QR payload %q does not refer to an item: %w", payload, ErrInvalidInput)
+	}
+	id = strings.Trim(id, "/")
+	if id == "" || strings.Contains(id, "/") {
+		return nil, fmt.Errorf("QR payload %q does not refer to an item: %w", payload, ErrInvalidInput)
+	}
+	var item Item
+	if err := c.getResource((&Item{ID: ID(id)}).IRI(), &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (o LabelOptions) withDefaults() LabelOptions {
+	if o.Client == nil {
+		o.Client = GetClient()
+	}
+	if o.BaseURL == "" {
+		o.BaseURL = o.Client.baseURL
+	}
+	if o.Format == "" {
+		o.Format = LabelSVG
+	}
+	return o
+}
+
+func (s LabelSheet) withDefaults() LabelSheet {
+	if s.PageWidth <= 0 {
+		s.PageWidth = 210
+	}
+	if s.PageHeight <= 0 {
+		s.PageHeight = 297
+	}
+	if s.Columns <= 0 {
+		s.Columns = 3
+	}
+	if s.Rows <= 0 {
+		s.Rows = 8
+	}
+	if s.LabelWidth <= 0 {
+		s.LabelWidth = (s.PageWidth - 2*s.MarginLeft - float64(s.Columns-1)*s.GapX) / float64(s.Columns)
+	}
+	if s.LabelHeight <= 0 {
+		s.LabelHeight = (s.PageHeight - 2*s.MarginTop - float64(s.Rows-1)*s.GapY) / float64(s.Rows)
+	}
+	return s
+}
+
+type labelBox struct {
+	label *Label
+	x, y  float64 // Top left corner in mm
+}
+
+// labelPageWriter renders sheets of labels in one output format.
+type labelPageWriter interface {
+	page(boxes []labelBox) error
+	finish(w io.Writer) error
+}
+
+// labelLayout places the parts of a label: the QR code on the left and the text lines beside it.
+type labelLayout struct {
+	qrX, qrY, qrSize float64
+	textX            float64
+	lines            []labelLine
+}
+
+type labelLine struct {
+	text string
+	y    float64 // Baseline in mm from the top of the page
+	size float64 // Font size in mm
+	bold bool
+}
+
+// labelPadding is the blank border inside each label, in mm.
+const labelPadding = 2.0
+
+func layoutLabel(b labelBox, sheet LabelSheet) labelLayout {
+	inner := sheet.LabelHeight - 2*labelPadding
+	l := labelLayout{qrX: b.x + labelPadding, qrY: b.y + labelPadding, qrSize: min(inner, sheet.LabelWidth/2)}
+	l.textX = l.qrX + l.qrSize + labelPadding
+	textWidth := b.x + sheet.LabelWidth - labelPadding - l.textX
+	nameSize := max(2, min(4.5, inner/5))
+	y := b.y + labelPadding
+	add := func(text string, size float64, bold bool) {
+		if text == "" || y+size*1.25 > b.y+sheet.LabelHeight-labelPadding+size*0.25 {
+			return
+		}
+		y += size * 1.1
+		l.lines = append(l.lines, labelLine{text: fitText(text, textWidth, size), y: y, size: size, bold: bold})
+		y += size * 0.15
+	}
+	add(b.label.Name, nameSize, true)
+	add(b.label.Path, nameSize*0.75, false)
+	for _, line := range b.label.Lines {
+		add(line, nameSize*0.75, false)
+	}
+	return l
+}
+
+// fitText shortens text to about the width that Helvetica at the given size takes.
+func fitText(text string, width, size float64) string {
+	limit := int(width / (size * 0.52))
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	if limit < 1 {
+		return ""
+	}
+	return string(runes[:limit-1]) + "…"
+}
+
+// qrModules returns the QR code of payload as rows of dark modules, without the quiet zone.
+func qrModules(payload string) ([][]bool, error) {
+	q, err := qrcode.New(payload, qrcode.Medium)
+	if err != nil {
+		return nil, fmt.Errorf("encoding QR code for %s: %w", payload, err)
+	}
+	q.DisableBorder = true
+	return q.Bitmap(), nil
+}
+
+// qrRuns calls fn for each horizontal run of dark modules, to keep the output small.
+func qrRuns(modules [][]bool, fn func(row, col, length int)) {
+	for r, row := range modules {
+		for c := 0; c < len(row); {
+			if !row[c] {
+				c++
+				continue
+			}
+			start := c
+			for c < len(row) && row[c] {
+				c++
+			}
+			fn(r, start, c-start)
+		}
+	}
+}
+
+type svgLabels struct {
+	sheet LabelSheet
+	pages int
+	buf   bytes.Buffer
+	n     int
+}
+
+func (s *svgLabels) page(boxes []labelBox) error {
+	offset := float64(s.n) * s.sheet.PageHeight
+	s.n++
+	fmt.Fprintf(&s.buf, "<g transform=\"translate(0 %g)\">\n", offset)
+	fmt.Fprintf(&s.buf, "<rect width=\"%g\" height=\"%g\" fill=\"#fff\"/>\n", s.sheet.PageWidth, s.sheet.PageHeight)
+	for _, b := range boxes {
+		modules, err := qrModules(b.label.URL)
+		if err != nil {
+			return err
+		}
+		l := layoutLabel(b, s.sheet)
+		unit := l.qrSize / float64(len(modules))
+		s.buf.WriteString("<path fill=\"#000\" d=\"")
+		qrRuns(modules, func(row, col, length int) {
+			fmt.Fprintf(&s.buf, "M%.3f %.3fh%.3fv%.3fh-%.3fz", l.qrX+float64(col)*unit, l.qrY+float64(row)*unit, float64(length)*unit, unit, float64(length)*unit)
+		})
+		s.buf.WriteString("\"/>\n")
+		for _, line := range l.lines {
+			weight := ""
+			if line.bold {
+				weight = ` font-weight="bold"`
+			}
+			fmt.Fprintf(&s.buf, "<text x=\"%.3f\" y=\"%.3f\" font-size=\"%.3f\"%s>%s</text>\n", l.textX, line.y, line.size, weight, html.EscapeString(line.text))
+		}
+	}
+	s.buf.WriteString("</g>\n")
+	return nil
+}
+
+func (s *svgLabels) finish(w io.Writer) error {
+	height := float64(s.pages) * s.sheet.PageHeight
+	fmt.Fprintf(w, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	fmt.Fprintf(w, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%gmm\" height=\"%gmm\" viewBox=\"0 0 %g %g\" font-family=\"Helvetica, Arial, sans-serif\">\n",
+		s.sheet.PageWidth, height, s.sheet.PageWidth, height)
+	if _, err := s.buf.WriteTo(w); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "</svg>\n")
+	return err
+}
+
+// mmToPt converts millimetres to PDF points.
+const mmToPt = 72 / 25.4
+
+// pdfLabels writes a minimal PDF using the standard Helvetica fonts, so no font needs embedding. Text is
+// encoded as WinAnsi; characters outside it print as "?".
+type pdfLabels struct {
+	sheet   LabelSheet
+	streams [][]byte
+}
+
+func (p *pdfLabels) page(boxes []labelBox) error {
+	var buf bytes.Buffer
+	height := p.sheet.PageHeight
+	enc := encoding.ReplaceUnsupported(charmap.Windows1252.NewEncoder())
+	for _, b := range boxes {
+		modules, err := qrModules(b.label.URL)
+		if err != nil {
+			return err
+		}
+		l := layoutLabel(b, p.sheet)
+		unit := l.qrSize / float64(len(modules))
+		buf.WriteString("0 g\n")
+		qrRuns(modules, func(row, col, length int) {
+			x := l.qrX + float64(col)*unit
+			y := height - (l.qrY + float64(row+1)*unit)
+			fmt.Fprintf(&buf, "%.3f %.3f %.3f %.3f re\n", x*mmToPt, y*mmToPt, float64(length)*unit*mmToPt, unit*mmToPt)
+		})
+		buf.WriteString("f\n")
+		for _, line := range l.lines {
+			text, _ := enc.String(line.text)
+			font := "F1"
+			if line.bold {
+				font = "F2"
+			}
+			fmt.Fprintf(&buf, "BT /%s %.3f Tf %.3f %.3f Td (%s) Tj ET\n", font, line.size*mmToPt, l.textX*mmToPt, (height-line.y)*mmToPt, pdfEscape(text))
+		}
+	}
+	p.streams = append(p.streams, buf.Bytes())
+	return nil
+}
+
+func (p *pdfLabels) finish(w io.Writer) error {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objects 1-4 are the catalog, the page tree and the fonts; each page then takes a page and a content object.
+	kids := make([]string, len(p.streams))
+	for i := range p.streams {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.streams)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, stream := range p.streams {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.3f %.3f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			p.sheet.PageWidth*mmToPt, p.sheet.PageHeight*mmToPt, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	_, err := out.WriteTo(w)
+	return err
+}
+
+func pdfEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`, "\r", `\r`, "\n", `\n`).Replace(s)
+}
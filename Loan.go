@@ -7,15 +7,15 @@ import (
 
 // Loan represents a loan record in Koillection, combining fields for JSON-LD and API interactions.
 type Loan struct {
-	Context    Context   `json:"@context,omitempty" access:"rw"`   // JSON-LD only
-	_ID        ID        `json:"@id,omitempty" access:"ro"`        // JSON-LD only
-	Type       string    `json:"@type,omitempty" access:"rw"`      // JSON-LD only
-	ID         ID        `json:"id,omitempty" access:"ro"`         // Identifier
-	Item       string    `json:"item" access:"rw"`                 // Item IRI
-	LentTo     string    `json:"lentTo" access:"rw"`               // Borrower name
-	LentAt     time.Time `json:"lentAt" access:"rw"`               // Loan start date
-	ReturnedAt time.Time `json:"returnedAt,omitempty" access:"rw"` // Loan return date
-	Owner      string    `json:"owner,omitempty" access:"ro"`      // Owner IRI
+	Context    Context             `json:"@context,omitempty" access:"rw"`                   // JSON-LD only
+	_ID        ID                  `json:"@id,omitempty" access:"ro"`                        // JSON-LD only
+	Type       string              `json:"@type,omitempty" access:"rw"`                      // JSON-LD only
+	ID         ID                  `json:"id,omitempty" access:"ro" render:"default"`        // Identifier
+	Item       string              `json:"item" access:"rw" render:"default"`                // Item IRI
+	LentTo     string              `json:"lentTo" access:"rw" render:"default"`              // Borrower name
+	LentAt     time.Time           `json:"lentAt" access:"rw" render:"default"`              // Loan start date
+	ReturnedAt Nullable[time.Time] `json:"returnedAt,omitzero" access:"rw" render:"default"` // Loan return date; null or unset while the loan is open
+	Owner      string              `json:"owner,omitempty" access:"ro"`                      // Owner IRI
 
 }
 
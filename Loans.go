@@ -0,0 +1,268 @@
+package koiApi
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrAlreadyLent is returned by Lend when the item has an open loan.
+var ErrAlreadyLent = errors.New("item already lent")
+
+// ErrAlreadyReturned is returned by Return when the loan is closed.
+var ErrAlreadyReturned = errors.New("loan already returned")
+
+// Open reports whether the loan has not been returned.
+func (l *Loan) Open() bool {
+	returned, ok := l.ReturnedAt.Get()
+	return !ok || returned.IsZero()
+}
+
+// Duration is how long the item was, or has so far been, lent out.
+func (l *Loan) Duration(now time.Time) time.Duration {
+	if !l.Open() {
+		return l.ReturnedAt.OrZero().Sub(l.LentAt)
+	}
+	return now.Sub(l.LentAt)
+}
+
+// Lend records that item was lent to borrower at the given time. It refuses with ErrAlreadyLent if the item
+// has an open loan.
+func Lend(ctx context.Context, item *Item, to string, at time.Time) (*Loan, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	loans, err := ListLoans(item)
+	if err != nil {
+		return nil, fmt.Errorf("listing loans of %s: %w", item.IRI(), err)
+	}
+	for _, l := range loans {
+		if l.Open() {
+			return l, fmt.Errorf("%s is lent to %s since %s: %w", item.IRI(), l.LentTo, l.LentAt.Format(time.DateOnly), ErrAlreadyLent)
+		}
+	}
+	loan := &Loan{Item: item.IRI(), LentTo: to, LentAt: at}
+	if err := loan.Validate(); err != nil {
+		return nil, err
+	}
+	return Create(loan)
+}
+
+// Return records that the loan ended at the given time.
+func Return(ctx context.Context, loan *Loan, at time.Time) (*Loan, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if !loan.Open() {
+		return loan, fmt.Errorf("%s: %w", loan.IRI(), ErrAlreadyReturned)
+	}
+	if at.Before(loan.LentAt) {
+		return loan, fmt.Errorf("return date %s is before the loan date %s: %w", at.Format(time.DateOnly), loan.LentAt.Format(time.DateOnly), ErrInvalidInput)
+	}
+	var returned Loan
+	if err := GetClient().patchResource(loan.IRI(), map[string]any{"returnedAt": at}, &returned); err != nil {
+		return loan, err
+	}
+	return &returned, nil
+}
+
+// LoanPolicy says when an open loan becomes overdue.
+type LoanPolicy struct {
+	DuePeriod  time.Duration            // Default time an item may stay lent out
+	ByBorrower map[string]time.Duration // Overrides by borrower name
+}
+
+// Due returns when the loan is due back, or the zero time if the policy sets no period.
+func (p LoanPolicy) Due(l *Loan) time.Time {
+	period, ok := p.ByBorrower[l.LentTo]
+	if !ok {
+		period = p.DuePeriod
+	}
+	if period <= 0 {
+		return time.Time{}
+	}
+	return l.LentAt.Add(period)
+}
+
+// LoanBook holds all loans together with the items they refer to.
+type LoanBook struct {
+	Loans []*Loan          // Sorted by LentAt
+	Items map[string]*Item // Keyed by item IRI; nil for items that no longer exist
+}
+
+// LoadLoans fetches every loan and the items they refer to through c, which may be the live client or a
+// CachedClient.
+func LoadLoans(c Client) (*LoanBook, error) {
+	loans, err := ListFrom(c, &Loan{})
+	if err != nil {
+		return nil, fmt.Errorf("listing loans: %w", err)
+	}
+	sort.SliceStable(loans, func(i, j int) bool { return loans[i].LentAt.Before(loans[j].LentAt) })
+	book := &LoanBook{Loans: loans, Items: map[string]*Item{}}
+	for _, l := range loans {
+		if _, ok := book.Items[l.Item]; ok || l.Item == "" {
+			continue
+		}
+		var item *Item
+		if err := c.getResource(l.Item, &item); errors.Is(err, ErrNotFound) {
+			item = nil // Recorded so that it is not fetched again
+		} else if err != nil {
+			return nil, fmt.Errorf("fetching %s: %w", l.Item, err)
+		}
+		book.Items[l.Item] = item
+	}
+	return book, nil
+}
+
+// Lent returns the open loans.
+func (b *LoanBook) Lent() []*Loan {
+	return b.filter(func(l *Loan) bool { return l.Open() })
+}
+
+// LentItems returns the items currently lent out.
+func (b *LoanBook) LentItems() []*Item {
+	var items []*Item
+	for _, l := range b.Lent() {
+		if item := b.Items[l.Item]; item != nil {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Borrowers returns the names of everyone who has borrowed something, sorted.
+func (b *LoanBook) Borrowers() []string {
+	seen := map[string]bool{}
+	for _, l := range b.Loans {
+		seen[l.LentTo] = true
+	}
+	return sortedKeys(seen)
+}
+
+// ByBorrower returns the loans to the named borrower, ignoring case.
+func (b *LoanBook) ByBorrower(name string) []*Loan {
+	return b.filter(func(l *Loan) bool { return strings.EqualFold(l.LentTo, name) })
+}
+
+// Overdue returns the open loans past their due date under policy at now.
+func (b *LoanBook) Overdue(policy LoanPolicy, now time.Time) []*Loan {
+	return b.filter(func(l *Loan) bool {
+		due := policy.Due(l)
+		return l.Open() && !due.IsZero() && now.After(due)
+	})
+}
+
+// History returns the loans of one item, oldest first.
+func (b *LoanBook) History(item *Item) []*Loan {
+	return b.filter(func(l *Loan) bool { return l.Item == item.IRI() })
+}
+
+func (b *LoanBook) filter(keep func(*Loan) bool) []*Loan {
+	var out []*Loan
+	for _, l := range b.Loans {
+		if keep(l) {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// LoanReportRow is one loan in a LoanReport.
+type LoanReportRow struct {
+	Item       string              `json:"item"`
+	ItemName   string              `json:"itemName"`
+	LentTo     string              `json:"lentTo"`
+	LentAt     time.Time           `json:"lentAt"`
+	ReturnedAt Nullable[time.Time] `json:"returnedAt,omitzero"`
+	Due        Nullable[time.Time] `json:"due,omitzero"`
+	Days       int                 `json:"days"` // Days lent out so far, or in total once returned
+	Overdue    bool                `json:"overdue"`
+}
+
+// LoanReport is a printable list of loans.
+type LoanReport struct {
+	Generated time.Time        `json:"generated"`
+	Rows      []*LoanReportRow `json:"rows"`
+}
+
+// Report builds a report of loans, evaluating due dates with policy at now.
+func (b *LoanBook) Report(loans []*Loan, policy LoanPolicy, now time.Time) *LoanReport {
+	r := &LoanReport{Generated: now}
+	for _, l := range loans {
+		row := &LoanReportRow{
+			Item:       l.Item,
+			LentTo:     l.LentTo,
+			LentAt:     l.LentAt,
+			ReturnedAt: l.ReturnedAt,
+			Days:       int(l.Duration(now).Hours() / 24),
+		}
+		if item := b.Items[l.Item]; item != nil {
+			row.ItemName = item.Name
+		}
+		if due := policy.Due(l); !due.IsZero() {
+			row.Due = NewNullable(due)
+			row.Overdue = l.Open() && now.After(due)
+		}
+		r.Rows = append(r.Rows, row)
+	}
+	return r
+}
+
+// Text renders the report as aligned columns.
+func (r *LoanReport) Text() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-30s %-20s %-10s %-10s %-10s %5s\n", "Item", "Lent to", "Lent", "Returned", "Due", "Days")
+	for _, row := range r.Rows {
+		overdue := ""
+		if row.Overdue {
+			overdue = "  OVERDUE"
+		}
+		name := row.ItemName
+		if name == "" {
+			name = row.Item // The item was deleted
+		}
+		fmt.Fprintf(&sb, "%-30.30s %-20.20s %-10s %-10s %-10s %5d%s\n",
+			name, row.LentTo, row.LentAt.Format(time.DateOnly), dateOrDash(row.ReturnedAt), dateOrDash(row.Due), row.Days, overdue)
+	}
+	return sb.String()
+}
+
+// CSV renders the report with a header row.
+func (r *LoanReport) CSV() (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"item", "itemName", "lentTo", "lentAt", "returnedAt", "due", "days", "overdue"})
+	for _, row := range r.Rows {
+		w.Write([]string{row.Item, row.ItemName, row.LentTo, row.LentAt.Format(time.RFC3339), timeOrEmpty(row.ReturnedAt),
+			timeOrEmpty(row.Due), strconv.Itoa(row.Days), strconv.FormatBool(row.Overdue)})
+	}
+	w.Flush()
+	return buf.String(), w.Error()
+}
+
+// JSON renders the report as indented JSON.
+func (r *LoanReport) JSON() (string, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	return string(data), err
+}
+
+func dateOrDash(n Nullable[time.Time]) string {
+	if t, ok := n.Get(); ok && !t.IsZero() {
+		return t.Format(time.DateOnly)
+	}
+	return "-"
+}
+
+func timeOrEmpty(n Nullable[time.Time]) string {
+	if t, ok := n.Get(); ok && !t.IsZero() {
+		return t.Format(time.RFC3339)
+	}
+	return ""
+}
@@ -0,0 +1,196 @@
+package koiApi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// Formatter formats and parses dates, numbers and prices the way a user's preferences ask for.
+type Formatter struct {
+	DateLayout string         // Go layout converted from User.DateFormat
+	Location   *time.Location // From User.Timezone; UTC if unset or unknown
+	Language   language.Tag   // From User.Locale
+	Currency   currency.Unit  // From User.Currency, used when a price has no currency of its own
+
+	printer *message.Printer
+	group   string // Digit grouping separator of Language
+	decimal string // Decimal separator of Language
+}
+
+// NewFormatter builds a Formatter from u's preferences. Missing preferences fall back to Y-m-d, UTC, English
+// and the machine's currency; invalid ones are errors.
+func NewFormatter(u *User) (*Formatter, error) {
+	layout, err := u.DateFormat.Layout()
+	if err != nil {
+		return nil, err
+	}
+	f := &Formatter{DateLayout: layout, Location: time.UTC, Language: language.English}
+	if u.Timezone != "" {
+		loc, err := time.LoadLocation(u.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("timezone %q: %w", u.Timezone, err)
+		}
+		f.Location = loc
+	}
+	if u.Locale != "" {
+		tag, err := language.Parse(strings.ReplaceAll(u.Locale, "_", "-"))
+		if err != nil {
+			return nil, fmt.Errorf("locale %q: %w", u.Locale, err)
+		}
+		f.Language = tag
+	}
+	code := u.Currency
+	if code == "" {
+		code = getDefaultCurrency()
+	}
+	unit, err := currency.ParseISO(code)
+	if err != nil {
+		return nil, fmt.Errorf("currency %q: %w", code, err)
+	}
+	f.Currency = unit
+
+	f.printer = message.NewPrinter(f.Language)
+	// The separators are read back from a sample so that parsing follows exactly what formatting produces.
+	sample := []rune(f.printer.Sprint(number.Decimal(1234.5)))
+	f.group, f.decimal = ",", "."
+	if len(sample) == 7 {
+		f.group, f.decimal = string(sample[1]), string(sample[5])
+	} else if len(sample) == 6 {
+		f.group, f.decimal = "", string(sample[4])
+	}
+	return f, nil
+}
+
+// FormatterFor fetches the user with the given ID through c and builds a Formatter from their preferences.
+func FormatterFor(c Client, id ID) (*Formatter, error) {
+	var u User
+	if err := c.getResource((&User{ID: id}).IRI(), &u); err != nil {
+		return nil, fmt.Errorf("fetching user %s: %w", id, err)
+	}
+	return NewFormatter(&u)
+}
+
+// Date formats t in the user's timezone and date format.
+func (f *Formatter) Date(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(f.Location).Format(f.DateLayout)
+}
+
+// DateTime formats t in the user's timezone as their date format followed by the time of day.
+func (f *Formatter) DateTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(f.Location).Format(f.DateLayout + " 15:04")
+}
+
+// Number formats v with the grouping and decimal separators of the user's locale.
+func (f *Formatter) Number(v float64) string {
+	return f.printer.Sprint(number.Decimal(v))
+}
+
+// Price formats amount in the given ISO 4217 currency, or the user's currency if code is empty, rounded the
+// way the currency is usually written and with the separators of the user's locale.
+func (f *Formatter) Price(amount float64, code string) (string, error) {
+	unit := f.Currency
+	if code != "" {
+		var err error
+		if unit, err = currency.ParseISO(code); err != nil {
+			return "", fmt.Errorf("currency %q: %w", code, err)
+		}
+	}
+	return f.printer.Sprint(currency.Symbol(unit.Amount(amount))), nil
+}
+
+// ParseDate parses a date typed in the user's date format, with or without a HH:MM time, as a time in the
+// user's timezone. RFC 3339 and Y-m-d input are accepted too.
+func (f *Formatter) ParseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{f.DateLayout, f.DateLayout + " 15:04", f.DateLayout + " 15:04:05", time.DateOnly, time.DateTime} {
+		if t, err := time.ParseInLocation(layout, s, f.Location); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("date %q does not match %s: %w", s, f.DateLayout, ErrInvalidInput)
+}
+
+// ParsePrice parses a price typed with the separators of the user's locale, such as "1.234,50 €" for de or
+// "EUR 12.5". The currency is taken from an ISO code or a symbol in the text and defaults to the user's.
+func (f *Formatter) ParsePrice(s string) (float64, currency.Unit, error) {
+	unit := f.Currency
+	var digits, rest strings.Builder
+	runes := []rune(strings.TrimSpace(s))
+	for i, r := range runes {
+		switch {
+		case unicode.IsDigit(r) || r == '-' || string(r) == f.decimal:
+			digits.WriteRune(r)
+		case string(r) == f.group && i > 0 && unicode.IsDigit(runes[i-1]):
+			// A grouping separator must be followed by exactly three digits, which catches input written with
+			// another locale's separators, e.g. 9.99 for de.
+			if !groupOfThree(runes[i+1:]) {
+				return 0, unit, fmt.Errorf("price %q: misplaced %q for locale %s: %w", s, r, f.Language, ErrInvalidInput)
+			}
+		case unicode.IsSpace(r):
+			// fr and others group with a (non-breaking) space; spaces also separate the amount from the symbol.
+		case r == '.' || r == ',':
+			return 0, unit, fmt.Errorf("price %q: unexpected %q for locale %s: %w", s, r, f.Language, ErrInvalidInput)
+		default:
+			rest.WriteRune(r)
+		}
+	}
+	if symbol := strings.TrimSpace(rest.String()); symbol != "" {
+		u, err := f.currencyFor(symbol)
+		if err != nil {
+			return 0, unit, err
+		}
+		unit = u
+	}
+	text := digits.String()
+	if f.decimal != "." {
+		text = strings.Replace(text, f.decimal, ".", 1)
+	}
+	amount, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, unit, fmt.Errorf("price %q: %w", s, ErrInvalidInput)
+	}
+	return amount, unit, nil
+}
+
+// currencyFor resolves an ISO code, or a symbol as printed for the user's currency or a few common ones.
+func (f *Formatter) currencyFor(symbol string) (currency.Unit, error) {
+	if u, err := currency.ParseISO(symbol); err == nil {
+		return u, nil
+	}
+	for _, u := range []currency.Unit{f.Currency, currency.EUR, currency.USD, currency.GBP, currency.JPY, currency.CHF} {
+		for _, kind := range []currency.Formatter{currency.Symbol, currency.NarrowSymbol} {
+			printed := f.printer.Sprint(kind(u.Amount(0)))
+			if strings.TrimSpace(strings.TrimRightFunc(printed, func(r rune) bool { return unicode.IsDigit(r) || r == '.' || r == ',' || unicode.IsSpace(r) })) == symbol {
+				return u, nil
+			}
+		}
+	}
+	return f.Currency, fmt.Errorf("unknown currency %q: %w", symbol, ErrInvalidInput)
+}
+
+// groupOfThree reports whether runes start with exactly three digits.
+func groupOfThree(runes []rune) bool {
+	for i := 0; i < 3; i++ {
+		if i >= len(runes) || !unicode.IsDigit(runes[i]) {
+			return false
+		}
+	}
+	return len(runes) == 3 || !unicode.IsDigit(runes[3])
+}
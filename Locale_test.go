@@ -0,0 +1,129 @@
+package koiApi
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/text/currency"
+)
+
+func testFormatter(t *testing.T, u *User) *Formatter {
+	t.Helper()
+	f, err := NewFormatter(u)
+	if err != nil {
+		t.Fatalf("NewFormatter(%+v): %v", u, err)
+	}
+	return f
+}
+
+func TestNewFormatter(t *testing.T) {
+	f := testFormatter(t, &User{Currency: "EUR"})
+	if f.DateLayout != time.DateOnly || f.Location != time.UTC || f.group != "," || f.decimal != "." {
+		t.Errorf("defaults = %q %s %q %q", f.DateLayout, f.Location, f.group, f.decimal)
+	}
+	for name, u := range map[string]*User{
+		"timezone":    {Timezone: "Mars/Olympus"},
+		"locale":      {Locale: "not a locale!"},
+		"currency":    {Currency: "XXXX"},
+		"date format": {DateFormat: "d.m.Y 1"},
+	} {
+		if _, err := NewFormatter(u); err == nil {
+			t.Errorf("invalid %s accepted", name)
+		}
+	}
+}
+
+func TestParsePrice(t *testing.T) {
+	de := testFormatter(t, &User{Locale: "de", Currency: "EUR"})
+	fr := testFormatter(t, &User{Locale: "fr", Currency: "EUR"})
+	en := testFormatter(t, &User{Locale: "en", Currency: "USD"})
+	tests := []struct {
+		f      *Formatter
+		in     string
+		amount float64
+		unit   currency.Unit
+	}{
+		{de, "1.234,50 €", 1234.5, currency.EUR},
+		{de, "12,5", 12.5, currency.EUR},
+		{de, "-3", -3, currency.EUR},
+		{de, "1.234.567", 1234567, currency.EUR},
+		{fr, "1\u202f234,50 €", 1234.5, currency.EUR}, // Narrow no-break space
+		{fr, "1\u00a0234,50", 1234.5, currency.EUR},   // No-break space, as formatted
+		{fr, "1 234,5 CHF", 1234.5, currency.CHF},
+		{en, "EUR 12.5", 12.5, currency.EUR},
+		{en, "1,234.50", 1234.5, currency.USD},
+		{en, "$ 3", 3, currency.USD},
+	}
+	for _, tt := range tests {
+		amount, unit, err := tt.f.ParsePrice(tt.in)
+		if err != nil || amount != tt.amount || unit != tt.unit {
+			t.Errorf("%s ParsePrice(%q) = %v %s, %v; want %v %s", tt.f.Language, tt.in, amount, unit, err, tt.amount, tt.unit)
+		}
+	}
+
+	for _, tt := range []struct {
+		f  *Formatter
+		in string
+	}{
+		{de, "9.99"},    // en decimal point
+		{de, "1.23,4"},  // Short group
+		{en, "12,5"},    // de decimal comma
+		{en, "1.2.3"},   // Two decimal points
+		{en, "12 XYZ"},  // Unknown currency
+		{en, ""},        // No amount
+		{fr, "12.50 €"}, // fr has no decimal point
+	} {
+		if amount, _, err := tt.f.ParsePrice(tt.in); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("%s ParsePrice(%q) = %v, %v; want ErrInvalidInput", tt.f.Language, tt.in, amount, err)
+		}
+	}
+}
+
+func TestFormatterRoundTrip(t *testing.T) {
+	for _, locale := range []string{"de", "fr", "en", "de_CH"} {
+		f := testFormatter(t, &User{Locale: locale, Currency: "EUR"})
+		s, err := f.Price(1234.5, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if amount, unit, err := f.ParsePrice(s); err != nil || amount != 1234.5 || unit != currency.EUR {
+			t.Errorf("%s: ParsePrice(%q) = %v %s, %v", locale, s, amount, unit, err)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	f := testFormatter(t, &User{DateFormat: DateFormatDMYSlash, Timezone: "Europe/Berlin", Currency: "EUR"})
+	berlin := f.Location
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"01/03/2024", time.Date(2024, 3, 1, 0, 0, 0, 0, berlin)},
+		{" 01/03/2024 18:30 ", time.Date(2024, 3, 1, 18, 30, 0, 0, berlin)},
+		{"01/03/2024 18:30:15", time.Date(2024, 3, 1, 18, 30, 15, 0, berlin)},
+		{"2024-03-01", time.Date(2024, 3, 1, 0, 0, 0, 0, berlin)},
+		{"2024-03-01T12:00:00Z", time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got, err := f.ParseDate(tt.in); err != nil || !got.Equal(tt.want) || (err == nil && got.Location() != tt.want.Location()) {
+			t.Errorf("ParseDate(%q) = %s, %v; want %s", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{"03/13/2024", "1 March 2024", ""} {
+		if _, err := f.ParseDate(in); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("ParseDate(%q) = %v, want ErrInvalidInput", in, err)
+		}
+	}
+	at := time.Date(2024, 3, 1, 23, 30, 0, 0, time.UTC)
+	if got := f.Date(at); got != "02/03/2024" {
+		t.Errorf("Date = %s, want the Berlin day", got)
+	}
+	if got := f.DateTime(at); got != "02/03/2024 00:30" {
+		t.Errorf("DateTime = %s", got)
+	}
+	if f.Date(time.Time{}) != "" {
+		t.Error("zero time is not empty")
+	}
+}
@@ -0,0 +1,678 @@
+package koiApi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest describes shared structure (tag categories, tags, choice lists, templates and a collection tree)
+// that should exist on the server. Objects are matched against the server by name/label.
+type Manifest struct {
+	TagCategories []ManifestTagCategory `json:"tagCategories,omitempty" yaml:"tagCategories,omitempty"` // Tag categories by label
+	Tags          []ManifestTag         `json:"tags,omitempty" yaml:"tags,omitempty"`                   // Tags by label
+	ChoiceLists   []ManifestChoiceList  `json:"choiceLists,omitempty" yaml:"choiceLists,omitempty"`     // Choice lists by name
+	Templates     []ManifestTemplate    `json:"templates,omitempty" yaml:"templates,omitempty"`         // Templates by name
+	Collections   []ManifestCollection  `json:"collections,omitempty" yaml:"collections,omitempty"`     // Root collections by title
+}
+
+// ManifestTagCategory describes a tag category.
+type ManifestTagCategory struct {
+	Label       string `json:"label" yaml:"label"`                                 // Category label
+	Description string `json:"description,omitempty" yaml:"description,omitempty"` // Category description
+	Color       string `json:"color,omitempty" yaml:"color,omitempty"`             // Color code
+}
+
+// ManifestTag describes a tag; Category refers to a tag category label.
+type ManifestTag struct {
+	Label       string     `json:"label" yaml:"label"`                                 // Tag label
+	Description string     `json:"description,omitempty" yaml:"description,omitempty"` // Tag description
+	Category    string     `json:"category,omitempty" yaml:"category,omitempty"`       // Tag category label
+	Visibility  Visibility `json:"visibility,omitempty" yaml:"visibility,omitempty"`   // Visibility level
+}
+
+// ManifestChoiceList describes a choice list.
+type ManifestChoiceList struct {
+	Name    string   `json:"name" yaml:"name"`       // Choice list name
+	Choices []string `json:"choices" yaml:"choices"` // List of choices
+}
+
+// ManifestTemplate describes a template and its fields.
+type ManifestTemplate struct {
+	Name   string          `json:"name" yaml:"name"`                         // Template name
+	Fields []ManifestField `json:"fields,omitempty" yaml:"fields,omitempty"` // Template fields
+}
+
+// ManifestField describes a template field; ChoiceList refers to a choice list name.
+// Position defaults to the field's index (1-based) within the template when omitted.
+type ManifestField struct {
+	Name       string     `json:"name" yaml:"name"`                                 // Field name
+	Type       FieldType  `json:"type" yaml:"type"`                                 // Field type
+	Position   int        `json:"position,omitempty" yaml:"position,omitempty"`     // Field position
+	ChoiceList string     `json:"choiceList,omitempty" yaml:"choiceList,omitempty"` // Choice list name
+	Visibility Visibility `json:"visibility,omitempty" yaml:"visibility,omitempty"` // Visibility level
+}
+
+// ManifestCollection describes a collection and its children; DefaultTemplate refers to a template name.
+type ManifestCollection struct {
+	Title           string               `json:"title" yaml:"title"`                                         // Collection title
+	Visibility      Visibility           `json:"visibility,omitempty" yaml:"visibility,omitempty"`           // Visibility level
+	DefaultTemplate string               `json:"defaultTemplate,omitempty" yaml:"defaultTemplate,omitempty"` // Items default template name
+	Children        []ManifestCollection `json:"children,omitempty" yaml:"children,omitempty"`               // Child collections
+}
+
+// PlanAction is the kind of change a PlanStep makes.
+type PlanAction string
+
+const (
+	PlanCreate PlanAction = "create"
+	PlanUpdate PlanAction = "update"
+	PlanDelete PlanAction = "delete"
+)
+
+// PlanStep is a single change computed by Manifest.Plan.
+type PlanStep struct {
+	Action  PlanAction // create, update or delete
+	Kind    string     // tagcategory, tag, choicelist, template, field or collection
+	Key     string     // Name/label; "template/field" for fields and "a/b/c" paths for collections
+	Changes []string   // Human readable list of differing fields for updates
+	run     func(*applyState) error
+}
+
+// Plan is the ordered list of changes needed to bring the server in line with a Manifest.
+type Plan struct {
+	Steps []*PlanStep
+	state *applyState
+}
+
+// applyState resolves manifest names to server IRIs while a plan is applied.
+type applyState struct {
+	tagCategories map[string]string // label -> IRI
+	choiceLists   map[string]string // name -> IRI
+	templates     map[string]string // name -> IRI
+	collections   map[string]string // path -> IRI
+}
+
+// LoadManifest reads a manifest from a .yaml, .yml or .json file.
+func LoadManifest(filename string) (*Manifest, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest %s: %w", filename, err)
+	}
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), ".")
+	return ParseManifest(data, format)
+}
+
+// ParseManifest decodes a manifest; format is "json", "yaml" or "yml".
+func ParseManifest(data []byte, format string) (*Manifest, error) {
+	var m Manifest
+	switch format {
+	case "json":
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("decoding manifest: %w", err)
+		}
+	case "yaml", "yml":
+		if err := yaml.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("decoding manifest: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown manifest format: %q", format)
+	}
+	return &m, m.Validate()
+}
+
+// Validate checks that names are present and unique. References to other objects are resolved when the plan
+// is applied, so they may name objects that already exist on the server but are not in the manifest.
+func (m *Manifest) Validate() error {
+	var errs []string
+	unique := func(kind string, names []string) {
+		seen := make(map[string]struct{})
+		for _, n := range names {
+			if n == "" {
+				errs = append(errs, fmt.Sprintf("%s name cannot be empty", kind))
+				continue
+			}
+			if _, exists := seen[n]; exists {
+				errs = append(errs, fmt.Sprintf("duplicate %s: %s", kind, n))
+			}
+			seen[n] = struct{}{}
+		}
+	}
+	var names []string
+	for _, tc := range m.TagCategories {
+		names = append(names, tc.Label)
+	}
+	unique("tag category", names)
+	names = nil
+	for _, t := range m.Tags {
+		names = append(names, t.Label)
+	}
+	unique("tag", names)
+	names = nil
+	for _, cl := range m.ChoiceLists {
+		names = append(names, cl.Name)
+	}
+	unique("choice list", names)
+	names = nil
+	for _, t := range m.Templates {
+		names = append(names, t.Name)
+		var fields []string
+		for _, f := range t.Fields {
+			fields = append(fields, f.Name)
+			if f.Type == FieldTypeChoiceList && f.ChoiceList == "" {
+				errs = append(errs, fmt.Sprintf("field %s/%s: choice-list fields need a choiceList", t.Name, f.Name))
+			}
+		}
+		unique("field in template "+t.Name, fields)
+	}
+	unique("template", names)
+	var walk func(prefix string, cols []ManifestCollection)
+	walk = func(prefix string, cols []ManifestCollection) {
+		var titles []string
+		for _, c := range cols {
+			titles = append(titles, c.Title)
+			walk(prefix+c.Title+"/", c.Children)
+		}
+		unique("collection under /"+prefix, titles)
+	}
+	walk("", m.Collections)
+	return validationErrors(&errs)
+}
+
+// Plan computes the changes needed to make the server match the manifest. Objects that exist on the server
+// but not in the manifest are only scheduled for deletion when prune is set.
+func (m *Manifest) Plan(prune bool) (*Plan, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	p := &Plan{state: &applyState{
+		tagCategories: map[string]string{},
+		choiceLists:   map[string]string{},
+		templates:     map[string]string{},
+		collections:   map[string]string{},
+	}}
+	var deletes [][]*PlanStep // one slice per kind, in dependency order
+
+	// Tag categories
+	categories, err := List(&TagCategory{})
+	if err != nil {
+		return nil, fmt.Errorf("listing tag categories: %w", err)
+	}
+	existingCategories := make(map[string]*TagCategory)
+	for _, tc := range categories {
+		existingCategories[tc.Label] = tc
+		p.state.tagCategories[tc.Label] = tc.IRI()
+	}
+	for _, want := range m.TagCategories {
+		want := want
+		have, ok := existingCategories[want.Label]
+		if !ok {
+			p.add(PlanCreate, "tagcategory", want.Label, nil, func(s *applyState) error {
+				created, err := Create(&TagCategory{Label: want.Label, Description: want.Description, Color: want.Color})
+				if err != nil {
+					return err
+				}
+				s.tagCategories[want.Label] = created.IRI()
+				return nil
+			})
+			continue
+		}
+		var changes []string
+		diffString(&changes, "description", have.Description, want.Description)
+		if want.Color != "" {
+			diffString(&changes, "color", have.Color, want.Color)
+		}
+		if len(changes) > 0 {
+			p.add(PlanUpdate, "tagcategory", want.Label, changes, func(s *applyState) error {
+				fields := map[string]any{}
+				if have.Description != want.Description {
+					fields["description"] = nullIfEmpty(want.Description)
+				}
+				if want.Color != "" && have.Color != want.Color {
+					fields["color"] = want.Color
+				}
+				return patchFields(have, fields)
+			})
+		}
+	}
+	if prune {
+		var keep []string
+		for _, tc := range m.TagCategories {
+			keep = append(keep, tc.Label)
+		}
+		deletes = append(deletes, pruneSteps("tagcategory", categories, func(tc *TagCategory) string { return tc.Label }, keep))
+	}
+
+	// Tags
+	tags, err := List(&Tag{})
+	if err != nil {
+		return nil, fmt.Errorf("listing tags: %w", err)
+	}
+	existingTags := make(map[string]*Tag)
+	for _, t := range tags {
+		existingTags[t.Label] = t
+	}
+	for _, want := range m.Tags {
+		want := want
+		have, ok := existingTags[want.Label]
+		if !ok {
+			p.add(PlanCreate, "tag", want.Label, nil, func(s *applyState) error {
+				category, err := resolveName(s.tagCategories, "tag category", want.Category)
+				if err != nil {
+					return err
+				}
+				_, err = Create(&Tag{Label: want.Label, Description: want.Description, Category: category, Visibility: want.Visibility})
+				return err
+			})
+			continue
+		}
+		var changes []string
+		diffString(&changes, "description", have.Description, want.Description)
+		if want.Visibility != "" {
+			diffString(&changes, "visibility", have.Visibility.String(), want.Visibility.String())
+		}
+		diffString(&changes, "category", p.labelFor(p.state.tagCategories, have.Category), want.Category)
+		if len(changes) > 0 {
+			p.add(PlanUpdate, "tag", want.Label, changes, func(s *applyState) error {
+				fields := map[string]any{}
+				if have.Description != want.Description {
+					fields["description"] = nullIfEmpty(want.Description)
+				}
+				if want.Visibility != "" && have.Visibility != want.Visibility {
+					fields["visibility"] = want.Visibility
+				}
+				category, err := resolveName(s.tagCategories, "tag category", want.Category)
+				if err != nil {
+					return err
+				}
+				if have.Category != category {
+					fields["category"] = nullIfEmpty(category)
+				}
+				return patchFields(have, fields)
+			})
+		}
+	}
+	if prune {
+		var keep []string
+		for _, t := range m.Tags {
+			keep = append(keep, t.Label)
+		}
+		deletes = append(deletes, pruneSteps("tag", tags, func(t *Tag) string { return t.Label }, keep))
+	}
+
+	// Choice lists
+	choiceLists, err := List(&ChoiceList{})
+	if err != nil {
+		return nil, fmt.Errorf("listing choice lists: %w", err)
+	}
+	existingChoiceLists := make(map[string]*ChoiceList)
+	for _, cl := range choiceLists {
+		existingChoiceLists[cl.Name] = cl
+		p.state.choiceLists[cl.Name] = cl.IRI()
+	}
+	for _, want := range m.ChoiceLists {
+		want := want
+		have, ok := existingChoiceLists[want.Name]
+		if !ok {
+			p.add(PlanCreate, "choicelist", want.Name, nil, func(s *applyState) error {
+				created, err := Create(&ChoiceList{Name: want.Name, Choices: want.Choices})
+				if err != nil {
+					return err
+				}
+				s.choiceLists[want.Name] = created.IRI()
+				return nil
+			})
+			continue
+		}
+		if !slices.Equal(have.Choices, want.Choices) {
+			changes := []string{fmt.Sprintf("choices: %v -> %v", have.Choices, want.Choices)}
+			p.add(PlanUpdate, "choicelist", want.Name, changes, func(s *applyState) error {
+				return patchFields(have, map[string]any{"choices": want.Choices})
+			})
+		}
+	}
+	if prune {
+		var keep []string
+		for _, cl := range m.ChoiceLists {
+			keep = append(keep, cl.Name)
+		}
+		deletes = append(deletes, pruneSteps("choicelist", choiceLists, func(cl *ChoiceList) string { return cl.Name }, keep))
+	}
+
+	// Templates and their fields
+	templates, err := List(&Template{})
+	if err != nil {
+		return nil, fmt.Errorf("listing templates: %w", err)
+	}
+	existingTemplates := make(map[string]*Template)
+	for _, t := range templates {
+		existingTemplates[t.Name] = t
+		p.state.templates[t.Name] = t.IRI()
+	}
+	var fieldDeletes []*PlanStep
+	for _, want := range m.Templates {
+		want := want
+		have, ok := existingTemplates[want.Name]
+		existingFields := make(map[string]*Field)
+		if !ok {
+			p.add(PlanCreate, "template", want.Name, nil, func(s *applyState) error {
+				created, err := Create(&Template{Name: want.Name})
+				if err != nil {
+					return err
+				}
+				s.templates[want.Name] = created.IRI()
+				return nil
+			})
+		} else {
+			fields, err := ListFields(have)
+			if err != nil {
+				return nil, fmt.Errorf("listing fields of template %s: %w", have.Name, err)
+			}
+			for _, f := range fields {
+				existingFields[f.Name] = f
+			}
+			if prune {
+				var keep []string
+				for _, f := range want.Fields {
+					keep = append(keep, f.Name)
+				}
+				fieldDeletes = append(fieldDeletes, pruneSteps("field", fields, func(f *Field) string { return want.Name + "/" + f.Name },
+					prefixAll(want.Name+"/", keep))...)
+			}
+		}
+		for i, wantField := range want.Fields {
+			wantField := wantField
+			if wantField.Position == 0 {
+				wantField.Position = i + 1
+			}
+			key := want.Name + "/" + wantField.Name
+			haveField, ok := existingFields[wantField.Name]
+			if !ok {
+				p.add(PlanCreate, "field", key, nil, func(s *applyState) error {
+					choiceList, err := resolveName(s.choiceLists, "choice list", wantField.ChoiceList)
+					if err != nil {
+						return err
+					}
+					_, err = Create(&Field{
+						Name:       wantField.Name,
+						FieldType:  wantField.Type,
+						Position:   wantField.Position,
+						ChoiceList: choiceList,
+						Template:   s.templates[want.Name],
+						Visibility: wantField.Visibility,
+					})
+					return err
+				})
+				continue
+			}
+			var changes []string
+			diffString(&changes, "type", haveField.FieldType.String(), wantField.Type.String())
+			diffString(&changes, "position", fmt.Sprint(haveField.Position), fmt.Sprint(wantField.Position))
+			diffString(&changes, "choiceList", p.labelFor(p.state.choiceLists, haveField.ChoiceList), wantField.ChoiceList)
+			if wantField.Visibility != "" {
+				diffString(&changes, "visibility", haveField.Visibility.String(), wantField.Visibility.String())
+			}
+			if len(changes) > 0 {
+				p.add(PlanUpdate, "field", key, changes, func(s *applyState) error {
+					fields := map[string]any{}
+					if haveField.FieldType != wantField.Type {
+						fields["type"] = wantField.Type
+					}
+					if haveField.Position != wantField.Position {
+						fields["position"] = wantField.Position
+					}
+					choiceList, err := resolveName(s.choiceLists, "choice list", wantField.ChoiceList)
+					if err != nil {
+						return err
+					}
+					if haveField.ChoiceList != choiceList {
+						fields["choiceList"] = nullIfEmpty(choiceList)
+					}
+					if wantField.Visibility != "" && haveField.Visibility != wantField.Visibility {
+						fields["visibility"] = wantField.Visibility
+					}
+					return patchFields(haveField, fields)
+				})
+			}
+		}
+	}
+	if prune {
+		deletes = append(deletes, fieldDeletes)
+		var keep []string
+		for _, t := range m.Templates {
+			keep = append(keep, t.Name)
+		}
+		deletes = append(deletes, pruneSteps("template", templates, func(t *Template) string { return t.Name }, keep))
+	}
+
+	// Collections, matched by their title path from the root
+	collections, err := List(&Collection{})
+	if err != nil {
+		return nil, fmt.Errorf("listing collections: %w", err)
+	}
+	paths := collectionPaths(collections)
+	existingCollections := make(map[string]*Collection)
+	for _, c := range collections {
+		existingCollections[paths[c.IRI()]] = c
+		p.state.collections[paths[c.IRI()]] = c.IRI()
+	}
+	var keepCollections []string
+	var walk func(parent string, cols []ManifestCollection)
+	walk = func(parent string, cols []ManifestCollection) {
+		for _, want := range cols {
+			want := want
+			path := want.Title
+			if parent != "" {
+				path = parent + "/" + want.Title
+			}
+			keepCollections = append(keepCollections, path)
+			have, ok := existingCollections[path]
+			if !ok {
+				p.add(PlanCreate, "collection", path, nil, func(s *applyState) error {
+					template, err := resolveName(s.templates, "template", want.DefaultTemplate)
+					if err != nil {
+						return err
+					}
+					c := &Collection{Title: want.Title, Visibility: want.Visibility, ItemsDefaultTemplate: template}
+					if parent != "" {
+						c.Parent = s.collections[parent]
+					}
+					created, err := Create(c)
+					if err != nil {
+						return err
+					}
+					s.collections[path] = created.IRI()
+					return nil
+				})
+			} else {
+				var changes []string
+				if want.Visibility != "" {
+					diffString(&changes, "visibility", have.Visibility.String(), want.Visibility.String())
+				}
+				diffString(&changes, "defaultTemplate", p.labelFor(p.state.templates, have.ItemsDefaultTemplate), want.DefaultTemplate)
+				if len(changes) > 0 {
+					p.add(PlanUpdate, "collection", path, changes, func(s *applyState) error {
+						fields := map[string]any{}
+						if want.Visibility != "" && have.Visibility != want.Visibility {
+							fields["visibility"] = want.Visibility
+						}
+						template, err := resolveName(s.templates, "template", want.DefaultTemplate)
+						if err != nil {
+							return err
+						}
+						if have.ItemsDefaultTemplate != template {
+							fields["itemsDefaultTemplate"] = nullIfEmpty(template)
+						}
+						return patchFields(have, fields)
+					})
+				}
+			}
+			walk(path, want.Children)
+		}
+	}
+	walk("", m.Collections)
+	if prune {
+		collectionDeletes := pruneSteps("collection", collections, func(c *Collection) string { return paths[c.IRI()] }, keepCollections)
+		// Delete children before their parents.
+		sort.SliceStable(collectionDeletes, func(i, j int) bool {
+			return strings.Count(collectionDeletes[i].Key, "/") > strings.Count(collectionDeletes[j].Key, "/")
+		})
+		deletes = append(deletes, collectionDeletes)
+	}
+
+	// Deletes run last, in reverse dependency order.
+	for i := len(deletes) - 1; i >= 0; i-- {
+		p.Steps = append(p.Steps, deletes[i]...)
+	}
+	return p, nil
+}
+
+// Apply computes and executes a plan for the manifest, returning the plan that was applied.
+func (m *Manifest) Apply(prune bool) (*Plan, error) {
+	p, err := m.Plan(prune)
+	if err != nil {
+		return nil, err
+	}
+	return p, p.Apply()
+}
+
+// Apply executes the plan's steps in order, stopping at the first failure.
+func (p *Plan) Apply() error {
+	for _, step := range p.Steps {
+		if err := step.run(p.state); err != nil {
+			return fmt.Errorf("%s %s %s: %w", step.Action, step.Kind, step.Key, err)
+		}
+	}
+	return nil
+}
+
+// Empty reports whether the plan has no changes.
+func (p *Plan) Empty() bool {
+	return len(p.Steps) == 0
+}
+
+// Summary returns one line per step, prefixed with +, ~ or - for create, update and delete.
+func (p *Plan) Summary() string {
+	var sb strings.Builder
+	for _, step := range p.Steps {
+		sb.WriteString(step.Summary())
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func (s *PlanStep) Summary() string {
+	sign := map[PlanAction]string{PlanCreate: "+", PlanUpdate: "~", PlanDelete: "-"}[s.Action]
+	line := fmt.Sprintf("%s %-12s %s", sign, s.Kind, s.Key)
+	if len(s.Changes) > 0 {
+		line += " (" + strings.Join(s.Changes, ", ") + ")"
+	}
+	return line
+}
+
+func (p *Plan) add(action PlanAction, kind, key string, changes []string, run func(*applyState) error) {
+	p.Steps = append(p.Steps, &PlanStep{Action: action, Kind: kind, Key: key, Changes: changes, run: run})
+}
+
+// labelFor maps an IRI back to the manifest name it was resolved from, or returns the IRI when unmanaged.
+func (p *Plan) labelFor(names map[string]string, iri string) string {
+	if iri == "" {
+		return ""
+	}
+	for name, v := range names {
+		if v == iri {
+			return name
+		}
+	}
+	return iri
+}
+
+// pruneSteps returns delete steps for server objects whose key is not in keep.
+func pruneSteps[T KoiObject](kind string, have []T, key func(T) string, keep []string) []*PlanStep {
+	var steps []*PlanStep
+	for _, obj := range have {
+		obj := obj
+		k := key(obj)
+		if slices.Contains(keep, k) {
+			continue
+		}
+		steps = append(steps, &PlanStep{Action: PlanDelete, Kind: kind, Key: k, run: func(*applyState) error {
+			return Delete(obj)
+		}})
+	}
+	return steps
+}
+
+// collectionPaths maps each collection IRI to its slash-separated title path from the root.
+func collectionPaths(collections []*Collection) map[string]string {
+	byIRI := make(map[string]*Collection, len(collections))
+	for _, c := range collections {
+		byIRI[c.IRI()] = c
+	}
+	paths := make(map[string]string, len(collections))
+	var pathOf func(c *Collection, depth int) string
+	pathOf = func(c *Collection, depth int) string {
+		if p, ok := paths[c.IRI()]; ok {
+			return p
+		}
+		path := c.Title
+		if parent, ok := byIRI[c.Parent]; ok && depth < len(collections) {
+			path = pathOf(parent, depth+1) + "/" + c.Title
+		}
+		paths[c.IRI()] = path
+		return path
+	}
+	for _, c := range collections {
+		pathOf(c, 0)
+	}
+	return paths
+}
+
+// resolveName looks up the IRI for a manifest reference; an empty name resolves to no reference.
+func resolveName(names map[string]string, kind, name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+	iri, ok := names[name]
+	if !ok {
+		return "", fmt.Errorf("unknown %s: %s", kind, name)
+	}
+	return iri, nil
+}
+
+// patchFields sends only the given fields of obj. Patching the whole object would drop cleared omitempty
+// fields, such as a removed description or default template, so the change would never reach the server.
+func patchFields(obj KoiObject, fields map[string]any) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	return GetClient().patchResource(obj.IRI(), fields, obj)
+}
+
+// nullIfEmpty returns nil, which clears the field, for an empty string.
+func nullIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func diffString(changes *[]string, name, have, want string) {
+	if have != want {
+		*changes = append(*changes, fmt.Sprintf("%s: %q -> %q", name, have, want))
+	}
+}
+
+func prefixAll(prefix string, s []string) []string {
+	out := make([]string, len(s))
+	for i, v := range s {
+		out[i] = prefix + v
+	}
+	return out
+}
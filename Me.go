@@ -0,0 +1,257 @@
+package koiApi
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+)
+
+// ErrFeatureDisabled is returned when an operation needs a feature the user has turned off.
+var ErrFeatureDisabled = errors.New("feature disabled")
+
+// Feature names a toggle in the user's settings.
+type Feature string
+
+const (
+	FeatureWishlists  Feature = "wishlists"
+	FeatureTags       Feature = "tags"
+	FeatureSigns      Feature = "signs"
+	FeatureAlbums     Feature = "albums"
+	FeatureLoans      Feature = "loans"
+	FeatureTemplates  Feature = "templates"
+	FeatureHistory    Feature = "history"
+	FeatureStatistics Feature = "statistics"
+	FeatureScraping   Feature = "scraping"
+)
+
+// Enabled reports whether the feature is turned on for u.
+func (u *User) Enabled(f Feature) bool {
+	switch f {
+	case FeatureWishlists:
+		return u.WishlistsFeatureEnabled
+	case FeatureTags:
+		return u.TagsFeatureEnabled
+	case FeatureSigns:
+		return u.SignsFeatureEnabled
+	case FeatureAlbums:
+		return u.AlbumsFeatureEnabled
+	case FeatureLoans:
+		return u.LoansFeatureEnabled
+	case FeatureTemplates:
+		return u.TemplatesFeatureEnabled
+	case FeatureHistory:
+		return u.HistoryFeatureEnabled
+	case FeatureStatistics:
+		return u.StatisticsFeatureEnabled
+	case FeatureScraping:
+		return u.ScrapingFeatureEnabled
+	}
+	return true
+}
+
+// Require returns ErrFeatureDisabled, naming the setting to change, if the feature is off for u.
+func (u *User) Require(f Feature) error {
+	if u.Enabled(f) {
+		return nil
+	}
+	return fmt.Errorf("%s are turned off for %s; enable %sFeatureEnabled in the user settings: %w", f, u.Username, f, ErrFeatureDisabled)
+}
+
+// userTTL is how long feature checks trust the cached user before fetching it again, so that a feature
+// toggled in the web interface is noticed by a long-running client.
+const userTTL = 5 * time.Minute
+
+// Me returns the authenticated user. The user is identified by the username in the JWT token, or the
+// configured login, and looked up in /api/users. Me always fetches the user, and the result replaces the copy
+// the client keeps for feature checks.
+func Me(ctx context.Context) (*User, error) {
+	return GetClient().me(ctx)
+}
+
+func (c *koiClient) me(ctx context.Context) (*User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	username := tokenUsername(c.token)
+	if username == "" && Auth.Username != nil {
+		username = *Auth.Username
+	}
+	var q []string
+	if username != "" {
+		q = append(q, "username="+username)
+	}
+	var users []*User
+	if err := c.listResources(baseObjPath(&User{}), &users, q...); err != nil {
+		return nil, fmt.Errorf("listing users: %w", err)
+	}
+	var found *User
+	for _, u := range users {
+		if username == "" && len(users) == 1 || u.Username == username {
+			found = u
+			break
+		}
+	}
+	if found == nil && username == "" {
+		return nil, fmt.Errorf("cannot tell which user is authenticated: the token has no username and %d users are visible, e.g. with an admin token; set Auth.Username: %w", len(users), ErrNotFound)
+	}
+	if found == nil {
+		return nil, fmt.Errorf("current user %q: %w", username, ErrNotFound)
+	}
+	c.setUser(found)
+	return found, nil
+}
+
+// setUser caches u as the authenticated user for feature checks; nil records that it could not be determined.
+func (c *koiClient) setUser(u *User) {
+	c.mu.Lock()
+	c.user, c.userAt = u, time.Now()
+	c.mu.Unlock()
+}
+
+// tokenUsername reads the username claim from a JWT without verifying it, or returns "".
+func tokenUsername(token string) string {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ""
+	}
+	var claims struct {
+		Username string `json:"username"`
+	}
+	json.Unmarshal(payload, &claims)
+	return claims.Username
+}
+
+// Preferences are the user settings UpdatePreferences can change; only the fields that are set are sent.
+type Preferences struct {
+	Currency                     Nullable[string]     `json:"currency,omitzero"` // ISO 4217 code
+	Locale                       Nullable[string]     `json:"locale,omitzero"`   // e.g. en, fr
+	Timezone                     Nullable[string]     `json:"timezone,omitzero"` // IANA name, e.g. Europe/Paris
+	DateFormat                   Nullable[DateFormat] `json:"dateFormat,omitzero"`
+	Visibility                   Nullable[Visibility] `json:"visibility,omitzero"`
+	WishlistsFeatureEnabled      Nullable[bool]       `json:"wishlistsFeatureEnabled,omitzero"`
+	TagsFeatureEnabled           Nullable[bool]       `json:"tagsFeatureEnabled,omitzero"`
+	SignsFeatureEnabled          Nullable[bool]       `json:"signsFeatureEnabled,omitzero"`
+	AlbumsFeatureEnabled         Nullable[bool]       `json:"albumsFeatureEnabled,omitzero"`
+	LoansFeatureEnabled          Nullable[bool]       `json:"loansFeatureEnabled,omitzero"`
+	TemplatesFeatureEnabled      Nullable[bool]       `json:"templatesFeatureEnabled,omitzero"`
+	HistoryFeatureEnabled        Nullable[bool]       `json:"historyFeatureEnabled,omitzero"`
+	StatisticsFeatureEnabled     Nullable[bool]       `json:"statisticsFeatureEnabled,omitzero"`
+	ScrapingFeatureEnabled       Nullable[bool]       `json:"scrapingFeatureEnabled,omitzero"`
+	SearchInDataByDefaultEnabled Nullable[bool]       `json:"searchInDataByDefaultEnabled,omitzero"`
+	DisplayItemsNameInGridView   Nullable[bool]       `json:"displayItemsNameInGridView,omitzero"`
+	SearchResultsDisplayMode     Nullable[string]     `json:"searchResultsDisplayMode,omitzero"`
+}
+
+// Validate checks the values that the server would otherwise reject with a bare 422.
+func (p *Preferences) Validate() error {
+	var errs []string
+	if code, ok := p.Currency.Get(); ok {
+		if _, err := currency.ParseISO(code); err != nil {
+			errs = append(errs, fmt.Sprintf("currency %q is not an ISO 4217 code", code))
+		}
+	}
+	if locale, ok := p.Locale.Get(); ok {
+		if _, err := language.Parse(strings.ReplaceAll(locale, "_", "-")); err != nil {
+			errs = append(errs, fmt.Sprintf("locale %q is not a language tag", locale))
+		}
+	}
+	if tz, ok := p.Timezone.Get(); ok {
+		if _, err := time.LoadLocation(tz); err != nil || tz == "" {
+			errs = append(errs, fmt.Sprintf("timezone %q is unknown", tz))
+		}
+	}
+	if df, ok := p.DateFormat.Get(); ok && df == "" {
+		errs = append(errs, "date format cannot be empty")
+	} else if _, err := df.Layout(); ok && err != nil {
+		errs = append(errs, fmt.Sprintf("date format %q has literals that cannot be converted to a Go layout", df))
+	}
+	if v, ok := p.Visibility.Get(); ok && v != VisibilityPublic && v != VisibilityInternal && v != VisibilityPrivate {
+		errs = append(errs, fmt.Sprintf("visibility %q is not public, internal or private", v))
+	}
+	return validationErrors(&errs)
+}
+
+// UpdatePreferences patches the authenticated user's preferences, leaving every other field, such as the
+// username, email and password, untouched.
+func UpdatePreferences(ctx context.Context, p Preferences) (*User, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	c := GetClient()
+	me, err := c.me(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var updated User
+	if err := c.patchResource(me.IRI(), p, &updated); err != nil {
+		return nil, err
+	}
+	c.setUser(&updated)
+	return &updated, nil
+}
+
+// requireFeature fails fast with ErrFeatureDisabled when the authenticated user has turned f off. The first
+// check on a client, and the first after userTTL, costs a request to /api/users. When the user cannot be
+// determined, e.g. the token has no username and several users are visible, or users cannot be listed, the
+// check passes and the server decides; the failed lookup is not retried until userTTL has passed.
+func (c *koiClient) requireFeature(ctx context.Context, f Feature) error {
+	c.mu.Lock()
+	u, fresh := c.user, !c.userAt.IsZero() && time.Since(c.userAt) <= userTTL
+	c.mu.Unlock()
+	if !fresh {
+		var err error
+		if u, err = c.me(ctx); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			c.setUser(nil)
+		}
+	}
+	if u == nil {
+		return nil
+	}
+	return u.Require(f)
+}
+
+// createResource posts in to create obj at path, after checking that the authenticated user has the feature
+// obj needs, if any. Every create goes through here so that none can bypass the check.
+func (c *koiClient) createResource(ctx context.Context, path string, obj KoiObject, in, out any) error {
+	if f, ok := featureFor(obj); ok {
+		if err := c.requireFeature(ctx, f); err != nil {
+			return err
+		}
+	}
+	return c.postResourceContext(ctx, path, in, out)
+}
+
+// featureFor returns the feature needed to create obj, if any.
+func featureFor(obj KoiObject) (Feature, bool) {
+	switch o := obj.(type) {
+	case *Wish, *Wishlist:
+		return FeatureWishlists, true
+	case *Tag, *TagCategory:
+		return FeatureTags, true
+	case *Album, *Photo:
+		return FeatureAlbums, true
+	case *Loan:
+		return FeatureLoans, true
+	case *Template, *Field:
+		return FeatureTemplates, true
+	case *Datum:
+		if o.DatumType == DatumTypeSign {
+			return FeatureSigns, true
+		}
+	}
+	return "", false
+}
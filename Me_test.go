@@ -0,0 +1,90 @@
+package koiApi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// meServer answers /api/users with users, or with status when users is empty, and accepts every create.
+func meServer(t *testing.T, status int, users string) (c *koiClient, lookups, posts *atomic.Int32) {
+	t.Helper()
+	lookups, posts = &atomic.Int32{}, &atomic.Int32{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/ld+json")
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/api/users"):
+			if r.URL.Query().Get("page") != "1" {
+				io.WriteString(w, `[]`)
+				return
+			}
+			lookups.Add(1)
+			if users == "" {
+				w.WriteHeader(status)
+				return
+			}
+			io.WriteString(w, users)
+		case r.Method == http.MethodPost:
+			posts.Add(1)
+			w.WriteHeader(http.StatusCreated)
+			io.WriteString(w, `{"@id":"/api/tags/1","id":"1","label":"new"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return NewKoiClient(srv.URL, time.Second), lookups, posts
+}
+
+func TestCreateWhenUserLookupFails(t *testing.T) {
+	for name, users := range map[string]string{
+		"forbidden":       "",
+		"several users":   `[{"@id":"/api/users/1","id":"1","username":"a"},{"@id":"/api/users/2","id":"2","username":"b"}]`,
+		"no users at all": `[]`,
+	} {
+		c, lookups, posts := meServer(t, http.StatusForbidden, users)
+		for i := 0; i < 2; i++ {
+			var out *Tag
+			if err := c.createResource(context.Background(), "/api/tags", &Tag{Label: "new"}, &Tag{Label: "new"}, &out); err != nil {
+				t.Errorf("%s: create failed: %v", name, err)
+			}
+		}
+		if posts.Load() != 2 {
+			t.Errorf("%s: %d creates reached the server, want 2", name, posts.Load())
+		}
+		if lookups.Load() != 1 {
+			t.Errorf("%s: user looked up %d times, want once per userTTL", name, lookups.Load())
+		}
+	}
+}
+
+func TestCreateWithFeatureDisabled(t *testing.T) {
+	c, _, posts := meServer(t, 0, `[{"@id":"/api/users/1","id":"1","username":"a","tagsFeatureEnabled":false}]`)
+	var out *Tag
+	err := c.createResource(context.Background(), "/api/tags", &Tag{Label: "new"}, &Tag{Label: "new"}, &out)
+	if !errors.Is(err, ErrFeatureDisabled) || posts.Load() != 0 {
+		t.Errorf("create = %v with %d posts, want ErrFeatureDisabled and none", err, posts.Load())
+	}
+	// Objects without a feature toggle are never checked.
+	if err := c.createResource(context.Background(), "/api/collections", &Collection{Title: "x"}, &Collection{Title: "x"}, &out); err != nil || posts.Load() != 1 {
+		t.Errorf("collection create = %v with %d posts", err, posts.Load())
+	}
+}
+
+func TestTokenUsername(t *testing.T) {
+	// {"username":"a"} in base64url, between a header and a signature that are not checked.
+	if got := tokenUsername("x.eyJ1c2VybmFtZSI6ImEifQ.y"); got != "a" {
+		t.Errorf("tokenUsername = %q", got)
+	}
+	for _, token := range []string{"", "x.y", "x.!!.y", "x.e30.y"} {
+		if got := tokenUsername(token); got != "" {
+			t.Errorf("tokenUsername(%q) = %q", token, got)
+		}
+	}
+}
@@ -1,6 +1,17 @@
 package koiApi
 
-import "fmt"
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
 
 // Metrics represents a map of metrics data.
 type Metrics string // Read-only
@@ -8,3 +19,351 @@ type Metrics string // Read-only
 func (m *Metrics) Summary() string {
 	return fmt.Sprintf("%-40s %s", string(*m), "")
 }
+
+// MetricType is the TYPE of a Prometheus metric family.
+type MetricType string
+
+const (
+	MetricTypeCounter   MetricType = "counter"
+	MetricTypeGauge     MetricType = "gauge"
+	MetricTypeHistogram MetricType = "histogram"
+	MetricTypeSummary   MetricType = "summary"
+	MetricTypeUntyped   MetricType = "untyped"
+)
+
+// MetricSample is one sample line, e.g. koillection_items{username="john"} 42.
+type MetricSample struct {
+	Name      string            `json:"name"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Value     float64           `json:"value"`
+	Timestamp int64             `json:"timestamp,omitempty"` // Milliseconds since the epoch; 0 when absent
+}
+
+// MetricFamily groups the samples sharing a HELP and TYPE.
+type MetricFamily struct {
+	Name    string          `json:"name"`
+	Help    string          `json:"help,omitempty"`
+	Type    MetricType      `json:"type"`
+	Samples []*MetricSample `json:"samples"`
+}
+
+// MetricSet is a parsed /api/metrics response.
+type MetricSet struct {
+	Families []*MetricFamily `json:"families"`
+	byName   map[string]*MetricFamily
+}
+
+// GetMetrics fetches and parses /api/metrics.
+func GetMetrics(ctx context.Context) (*MetricSet, error) {
+	raw, err := GetClient().getMetrics(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return raw.Parse()
+}
+
+func (c *koiClient) getMetrics(ctx context.Context) (Metrics, error) {
+	resp, err := c.doRequestContext(ctx, http.MethodGet, "/api/metrics", nil, "")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading metrics: %w", err)
+	}
+	return Metrics(body), nil
+}
+
+// Parse parses the Prometheus text exposition format.
+func (m Metrics) Parse() (*MetricSet, error) {
+	set := &MetricSet{byName: map[string]*MetricFamily{}}
+	scanner := bufio.NewScanner(strings.NewReader(string(m)))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			fields := strings.SplitN(strings.TrimSpace(strings.TrimPrefix(line, "#")), " ", 3)
+			if len(fields) < 3 {
+				continue // plain comment
+			}
+			switch fields[0] {
+			case "HELP":
+				set.family(fields[1]).Help = unescapeMetricHelp(fields[2])
+			case "TYPE":
+				set.family(fields[1]).Type = MetricType(strings.ToLower(fields[2]))
+			}
+			continue
+		}
+		sample, err := parseMetricSample(line)
+		if err != nil {
+			return nil, fmt.Errorf("metrics line %d: %w", lineNo, err)
+		}
+		f := set.familyForSample(sample.Name)
+		f.Samples = append(f.Samples, sample)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading metrics: %w", err)
+	}
+	return set, nil
+}
+
+func (s *MetricSet) family(name string) *MetricFamily {
+	if f, ok := s.byName[name]; ok {
+		return f
+	}
+	f := &MetricFamily{Name: name, Type: MetricTypeUntyped}
+	s.byName[name] = f
+	s.Families = append(s.Families, f)
+	return f
+}
+
+// familyForSample finds the family of a sample, mapping histogram and summary series such as
+// x_bucket, x_sum and x_count back to x.
+func (s *MetricSet) familyForSample(name string) *MetricFamily {
+	for _, suffix := range []string{"_bucket", "_sum", "_count"} {
+		base := strings.TrimSuffix(name, suffix)
+		if f, ok := s.byName[base]; ok && base != name && (f.Type == MetricTypeHistogram || f.Type == MetricTypeSummary) {
+			return f
+		}
+	}
+	return s.family(name)
+}
+
+// Family returns the named metric family.
+func (s *MetricSet) Family(name string) (*MetricFamily, bool) {
+	f, ok := s.byName[name]
+	return f, ok
+}
+
+// Value returns the value of the first sample named name whose labels include all of labels.
+func (s *MetricSet) Value(name string, labels map[string]string) (float64, bool) {
+	for _, f := range s.Families {
+		for _, sample := range f.Samples {
+			if sample.Name == name && sample.hasLabels(labels) {
+				return sample.Value, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// userLabels are the label names that identify a user in Koillection metrics.
+var userLabels = []string{"username", "user"}
+
+// Users returns the user names that appear in per-user samples.
+func (s *MetricSet) Users() []string {
+	seen := map[string]bool{}
+	for _, f := range s.Families {
+		for _, sample := range f.Samples {
+			if u := sample.user(); u != "" {
+				seen[u] = true
+			}
+		}
+	}
+	return sortedKeys(seen)
+}
+
+// ForUser returns the per-user samples for username, keyed by the series without its user label, such as
+// koillection_items or koillection_items{type="books"}.
+func (s *MetricSet) ForUser(username string) map[string]float64 {
+	out := map[string]float64{}
+	for _, f := range s.Families {
+		for _, sample := range f.Samples {
+			if sample.user() == username {
+				out[sample.seriesWithout(userLabels...)] = sample.Value
+			}
+		}
+	}
+	return out
+}
+
+// WriteTo writes the set back out in the Prometheus text exposition format, so it can be re-exported.
+func (s *MetricSet) WriteTo(w io.Writer) (int64, error) {
+	var sb strings.Builder
+	for _, f := range s.Families {
+		if f.Help != "" {
+			fmt.Fprintf(&sb, "# HELP %s %s\n", f.Name, escapeMetricHelp(f.Help))
+		}
+		fmt.Fprintf(&sb, "# TYPE %s %s\n", f.Name, f.Type)
+		for _, sample := range f.Samples {
+			sb.WriteString(sample.String())
+			sb.WriteString("\n")
+		}
+	}
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
+
+// String renders the sample as an exposition line.
+func (m *MetricSample) String() string {
+	var sb strings.Builder
+	sb.WriteString(m.seriesWithout())
+	sb.WriteString(" ")
+	sb.WriteString(formatMetricValue(m.Value))
+	if m.Timestamp != 0 {
+		fmt.Fprintf(&sb, " %d", m.Timestamp)
+	}
+	return sb.String()
+}
+
+// Summary
+func (f *MetricFamily) Summary() string {
+	return fmt.Sprintf("%-40s %-10s %d samples", f.Name, f.Type, len(f.Samples))
+}
+
+// seriesWithout renders the name and labels of the sample, leaving out the given label names.
+func (m *MetricSample) seriesWithout(names ...string) string {
+	var sb strings.Builder
+	sb.WriteString(m.Name)
+	n := 0
+	for _, k := range sortedKeys(m.Labels) {
+		if slices.Contains(names, k) {
+			continue
+		}
+		if n == 0 {
+			sb.WriteString("{")
+		} else {
+			sb.WriteString(",")
+		}
+		fmt.Fprintf(&sb, "%s=\"%s\"", k, escapeMetricLabel(m.Labels[k]))
+		n++
+	}
+	if n > 0 {
+		sb.WriteString("}")
+	}
+	return sb.String()
+}
+
+func (m *MetricSample) hasLabels(labels map[string]string) bool {
+	for k, v := range labels {
+		if m.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *MetricSample) user() string {
+	for _, l := range userLabels {
+		if u, ok := m.Labels[l]; ok {
+			return u
+		}
+	}
+	return ""
+}
+
+// parseMetricSample parses name{label="value",...} value [timestamp].
+func parseMetricSample(line string) (*MetricSample, error) {
+	sample := &MetricSample{}
+	i := strings.IndexAny(line, "{ \t")
+	if i < 0 {
+		return nil, fmt.Errorf("missing value: %s", line)
+	}
+	sample.Name = line[:i]
+	rest := line[i:]
+	if strings.HasPrefix(rest, "{") {
+		labels, n, err := parseMetricLabels(rest)
+		if err != nil {
+			return nil, err
+		}
+		sample.Labels = labels
+		rest = rest[n:]
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, fmt.Errorf("malformed sample: %s", line)
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q: %w", fields[0], err)
+	}
+	sample.Value = value
+	if len(fields) == 2 {
+		if sample.Timestamp, err = strconv.ParseInt(fields[1], 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid timestamp %q: %w", fields[1], err)
+		}
+	}
+	return sample, nil
+}
+
+// parseMetricLabels parses a {k="v",...} block at the start of s and returns the labels and bytes consumed.
+func parseMetricLabels(s string) (map[string]string, int, error) {
+	labels := map[string]string{}
+	i := 1
+	for {
+		for i < len(s) && (s[i] == ' ' || s[i] == ',') {
+			i++
+		}
+		if i >= len(s) {
+			return nil, 0, fmt.Errorf("unterminated labels: %s", s)
+		}
+		if s[i] == '}' {
+			return labels, i + 1, nil
+		}
+		eq := strings.IndexByte(s[i:], '=')
+		if eq < 0 || i+eq+1 >= len(s) || s[i+eq+1] != '"' {
+			return nil, 0, fmt.Errorf("malformed label: %s", s[i:])
+		}
+		name := strings.TrimSpace(s[i : i+eq])
+		i += eq + 2
+		var value strings.Builder
+		for ; i < len(s) && s[i] != '"'; i++ {
+			if s[i] == '\\' && i+1 < len(s) {
+				i++
+				switch s[i] {
+				case 'n':
+					value.WriteByte('\n')
+				default:
+					value.WriteByte(s[i])
+				}
+				continue
+			}
+			value.WriteByte(s[i])
+		}
+		if i >= len(s) {
+			return nil, 0, fmt.Errorf("unterminated label value: %s", s)
+		}
+		labels[name] = value.String()
+		i++
+	}
+}
+
+func formatMetricValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func unescapeMetricHelp(s string) string {
+	return strings.NewReplacer(`\\`, `\`, `\n`, "\n").Replace(s)
+}
+
+func escapeMetricLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+func escapeMetricHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+// Names returns the family names in sorted order.
+func (s *MetricSet) Names() []string {
+	names := make([]string, len(s.Families))
+	for i, f := range s.Families {
+		names[i] = f.Name
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,131 @@
+package koiApi
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMetricSample(t *testing.T) {
+	tests := []struct {
+		line string
+		want MetricSample
+	}{
+		{"koillection_users 3", MetricSample{Name: "koillection_users", Value: 3}},
+		{"koillection_users 3 1700000000000", MetricSample{Name: "koillection_users", Value: 3, Timestamp: 1700000000000}},
+		{`koillection_items{username="john"} 42`, MetricSample{Name: "koillection_items", Labels: map[string]string{"username": "john"}, Value: 42}},
+		{`x{a="1", b="2",} 1.5e3`, MetricSample{Name: "x", Labels: map[string]string{"a": "1", "b": "2"}, Value: 1500}},
+		{`x{path="C:\\dir",quote="say \"hi\"",nl="a\nb"} 1`, MetricSample{Name: "x", Labels: map[string]string{"path": `C:\dir`, "quote": `say "hi"`, "nl": "a\nb"}, Value: 1}},
+		{`x{brace="}"} 1`, MetricSample{Name: "x", Labels: map[string]string{"brace": "}"}, Value: 1}},
+		{`x_bucket{le="+Inf"} +Inf`, MetricSample{Name: "x_bucket", Labels: map[string]string{"le": "+Inf"}, Value: math.Inf(1)}},
+		{"x -Inf", MetricSample{Name: "x", Value: math.Inf(-1)}},
+	}
+	for _, tt := range tests {
+		got, err := parseMetricSample(tt.line)
+		if err != nil {
+			t.Errorf("parseMetricSample(%q): %v", tt.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(*got, tt.want) {
+			t.Errorf("parseMetricSample(%q) = %+v, want %+v", tt.line, *got, tt.want)
+		}
+	}
+
+	nan, err := parseMetricSample("x NaN")
+	if err != nil || !math.IsNaN(nan.Value) {
+		t.Errorf("parseMetricSample(x NaN) = %v, %v", nan, err)
+	}
+
+	for _, line := range []string{"x", "x one", "x 1 2 3", `x{a="1" 1`, `x{a=1} 1`, `x{a="1} 1`, "x 1 soon"} {
+		if _, err := parseMetricSample(line); err == nil {
+			t.Errorf("parseMetricSample(%q) succeeded, want an error", line)
+		}
+	}
+}
+
+const testMetrics = `# HELP koillection_items Number of items per user
+# TYPE koillection_items gauge
+koillection_items{username="john",type="books"} 12
+koillection_items{username="john",type="stamps"} 30
+koillection_items{username="jane",type="books"} 5
+# a plain comment
+# HELP koillection_request_seconds Request duration\nin seconds, with a \\ backslash
+# TYPE koillection_request_seconds HISTOGRAM
+koillection_request_seconds_bucket{le="0.5"} 3
+koillection_request_seconds_bucket{le="+Inf"} 4
+koillection_request_seconds_sum 1.25
+koillection_request_seconds_count 4
+
+koillection_untyped NaN
+`
+
+func TestMetricsParse(t *testing.T) {
+	set, err := Metrics(testMetrics).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"koillection_items", "koillection_request_seconds", "koillection_untyped"}; !reflect.DeepEqual(set.Names(), want) {
+		t.Fatalf("names = %v, want %v", set.Names(), want)
+	}
+
+	items, _ := set.Family("koillection_items")
+	if items.Type != MetricTypeGauge || items.Help != "Number of items per user" || len(items.Samples) != 3 {
+		t.Errorf("items family = %+v", items)
+	}
+	hist, _ := set.Family("koillection_request_seconds")
+	if hist.Type != MetricTypeHistogram || len(hist.Samples) != 4 {
+		t.Errorf("histogram family = %+v", hist)
+	}
+	if want := "Request duration\nin seconds, with a \\ backslash"; hist.Help != want {
+		t.Errorf("help = %q, want %q", hist.Help, want)
+	}
+	if v, ok := set.Value("koillection_request_seconds_bucket", map[string]string{"le": "+Inf"}); !ok || v != 4 {
+		t.Errorf("+Inf bucket = %v, %v", v, ok)
+	}
+	untyped, _ := set.Family("koillection_untyped")
+	if untyped.Type != MetricTypeUntyped || !math.IsNaN(untyped.Samples[0].Value) {
+		t.Errorf("untyped family = %+v", untyped)
+	}
+
+	if _, err := Metrics("x{a=\"1\"} one\n").Parse(); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("parse error = %v, want the line number", err)
+	}
+}
+
+func TestMetricsForUser(t *testing.T) {
+	set, err := Metrics(testMetrics).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"jane", "john"}; !reflect.DeepEqual(set.Users(), want) {
+		t.Errorf("users = %v, want %v", set.Users(), want)
+	}
+	want := map[string]float64{`koillection_items{type="books"}`: 12, `koillection_items{type="stamps"}`: 30}
+	if got := set.ForUser("john"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ForUser(john) = %v, want %v", got, want)
+	}
+}
+
+func TestMetricsRoundTrip(t *testing.T) {
+	set, err := Metrics(testMetrics).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	if _, err := set.WriteTo(&sb); err != nil {
+		t.Fatal(err)
+	}
+	again, err := Metrics(sb.String()).Parse()
+	if err != nil {
+		t.Fatalf("reparsing %q: %v", sb.String(), err)
+	}
+	var sb2 strings.Builder
+	again.WriteTo(&sb2)
+	if sb.String() != sb2.String() {
+		t.Errorf("round trip changed the output:\n%s\n---\n%s", sb.String(), sb2.String())
+	}
+	if !strings.Contains(sb.String(), `koillection_request_seconds_bucket{le="+Inf"} 4`) || !strings.Contains(sb.String(), "koillection_untyped NaN") {
+		t.Errorf("unexpected output:\n%s", sb.String())
+	}
+}
@@ -0,0 +1,123 @@
+package koiApi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Nullable is a value with three states: unset, null and set. Tag fields of this type with `omitzero` so that
+// unset fields are left out of requests while null ones are sent as JSON null, which lets a PATCH clear them.
+type Nullable[T any] struct {
+	value T
+	state nullableState
+}
+
+type nullableState uint8
+
+const (
+	nullableUnset nullableState = iota
+	nullableNull
+	nullableSet
+)
+
+// NewNullable returns a Nullable holding v.
+func NewNullable[T any](v T) Nullable[T] {
+	return Nullable[T]{value: v, state: nullableSet}
+}
+
+// Null returns a Nullable that marshals as JSON null.
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{state: nullableNull}
+}
+
+// Get returns the value and whether one is set.
+func (n Nullable[T]) Get() (T, bool) {
+	return n.value, n.state == nullableSet
+}
+
+// Or returns the value, or def when none is set.
+func (n Nullable[T]) Or(def T) T {
+	if n.state == nullableSet {
+		return n.value
+	}
+	return def
+}
+
+// OrZero returns the value, or the zero value of T when none is set.
+func (n Nullable[T]) OrZero() T {
+	return n.value
+}
+
+// IsSet reports whether a value is set.
+func (n Nullable[T]) IsSet() bool {
+	return n.state == nullableSet
+}
+
+// IsNull reports whether the field is explicitly null.
+func (n Nullable[T]) IsNull() bool {
+	return n.state == nullableNull
+}
+
+// IsZero reports whether the field is unset; encoding/json uses it for `omitzero`.
+func (n Nullable[T]) IsZero() bool {
+	return n.state == nullableUnset
+}
+
+// Set stores v.
+func (n *Nullable[T]) Set(v T) {
+	n.value, n.state = v, nullableSet
+}
+
+// SetNull marks the field as explicitly null.
+func (n *Nullable[T]) SetNull() {
+	var zero T
+	n.value, n.state = zero, nullableNull
+}
+
+// Unset forgets the field so it is left out of requests.
+func (n *Nullable[T]) Unset() {
+	var zero T
+	n.value, n.state = zero, nullableUnset
+}
+
+// MarshalJSON
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if n.state != nullableSet {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.value)
+}
+
+// UnmarshalJSON
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		n.SetNull()
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	n.Set(v)
+	return nil
+}
+
+// MarshalYAML
+func (n Nullable[T]) MarshalYAML() (any, error) {
+	if n.state != nullableSet {
+		return nil, nil
+	}
+	return n.value, nil
+}
+
+// String renders the value, "null" or "<unset>".
+func (n Nullable[T]) String() string {
+	switch n.state {
+	case nullableSet:
+		return fmt.Sprint(n.value)
+	case nullableNull:
+		return "null"
+	}
+	return "<unset>"
+}
@@ -0,0 +1,127 @@
+package koiApi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+type nullableDoc struct {
+	Name  Nullable[string]    `json:"name,omitzero"`
+	Count Nullable[int]       `json:"count,omitzero"`
+	At    Nullable[time.Time] `json:"at,omitzero"`
+}
+
+func TestNullableMarshal(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		doc  nullableDoc
+		want string
+	}{
+		{nullableDoc{}, `{}`},
+		{nullableDoc{Name: Null[string]()}, `{"name":null}`},
+		{nullableDoc{Name: NewNullable("")}, `{"name":""}`},
+		{nullableDoc{Count: NewNullable(0)}, `{"count":0}`},
+		{nullableDoc{Name: NewNullable("a"), Count: Null[int](), At: NewNullable(at)}, `{"name":"a","count":null,"at":"2024-03-01T12:00:00Z"}`},
+	}
+	for _, tt := range tests {
+		got, err := json.Marshal(tt.doc)
+		if err != nil {
+			t.Errorf("Marshal(%+v): %v", tt.doc, err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("Marshal(%+v) = %s, want %s", tt.doc, got, tt.want)
+		}
+	}
+}
+
+func TestNullableUnmarshal(t *testing.T) {
+	tests := []struct {
+		json                string
+		isSet, isNull, zero bool
+		value               string
+	}{
+		{`{}`, false, false, true, ""},
+		{`{"name":null}`, false, true, false, ""},
+		{`{"name": null }`, false, true, false, ""},
+		{`{"name":""}`, true, false, false, ""},
+		{`{"name":"a"}`, true, false, false, "a"},
+	}
+	for _, tt := range tests {
+		var doc nullableDoc
+		if err := json.Unmarshal([]byte(tt.json), &doc); err != nil {
+			t.Errorf("Unmarshal(%s): %v", tt.json, err)
+			continue
+		}
+		v, ok := doc.Name.Get()
+		if ok != tt.isSet || doc.Name.IsSet() != tt.isSet || doc.Name.IsNull() != tt.isNull || doc.Name.IsZero() != tt.zero || v != tt.value {
+			t.Errorf("Unmarshal(%s) = %s (set %v, null %v, zero %v)", tt.json, doc.Name, doc.Name.IsSet(), doc.Name.IsNull(), doc.Name.IsZero())
+		}
+	}
+
+	var doc nullableDoc
+	if err := json.Unmarshal([]byte(`{"count":"x"}`), &doc); err == nil {
+		t.Error("Unmarshal of a string into Nullable[int] succeeded")
+	}
+}
+
+func TestNullableRoundTrip(t *testing.T) {
+	for _, in := range []string{`{}`, `{"name":null}`, `{"name":"a","count":0}`, `{"count":null,"at":"2024-03-01T12:00:00Z"}`} {
+		var doc nullableDoc
+		if err := json.Unmarshal([]byte(in), &doc); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", in, err)
+		}
+		out, err := json.Marshal(doc)
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		if string(out) != in {
+			t.Errorf("round trip of %s gave %s", in, out)
+		}
+	}
+}
+
+func TestNullableAccessors(t *testing.T) {
+	var n Nullable[int]
+	if n.Or(7) != 7 || n.OrZero() != 0 || n.String() != "<unset>" {
+		t.Errorf("unset: Or %d, OrZero %d, String %q", n.Or(7), n.OrZero(), n.String())
+	}
+	n.Set(3)
+	if n.Or(7) != 3 || n.String() != "3" {
+		t.Errorf("set: Or %d, String %q", n.Or(7), n.String())
+	}
+	n.SetNull()
+	if !n.IsNull() || n.Or(7) != 7 || n.OrZero() != 0 || n.String() != "null" {
+		t.Errorf("null: %+v", n)
+	}
+	n.Unset()
+	if !n.IsZero() {
+		t.Errorf("Unset left %+v", n)
+	}
+}
+
+func TestLoanReturnedAt(t *testing.T) {
+	var loan Loan
+	if err := json.Unmarshal([]byte(`{"lentTo":"Ann","lentAt":"2024-03-01T00:00:00Z","returnedAt":null}`), &loan); err != nil {
+		t.Fatal(err)
+	}
+	if loan.ReturnedAt.IsSet() {
+		t.Errorf("open loan has ReturnedAt %s", loan.ReturnedAt)
+	}
+	// Clearing the return date must be sent as null, and leaving it alone must send nothing.
+	loan = Loan{LentTo: "Ann", ReturnedAt: Null[time.Time]()}
+	data, _ := json.Marshal(loan)
+	var fields map[string]any
+	json.Unmarshal(data, &fields)
+	if v, ok := fields["returnedAt"]; !ok || v != nil {
+		t.Errorf("cleared returnedAt encoded as %s", data)
+	}
+	loan.ReturnedAt.Unset()
+	data, _ = json.Marshal(loan)
+	fields = nil
+	json.Unmarshal(data, &fields)
+	if _, ok := fields["returnedAt"]; ok {
+		t.Errorf("unset returnedAt encoded as %s", data)
+	}
+}
@@ -9,24 +9,24 @@ type PhotoImage *string
 
 // Photo represents a photo in Koillection, combining fields for JSON-LD and API interactions.
 type Photo struct {
-	Context             Context    `json:"@context,omitempty" access:"rw"`            // JSON-LD only
-	_ID                 ID         `json:"@id,omitempty" access:"ro"`                 // JSON-LD only
-	Type                string     `json:"@type,omitempty" access:"rw"`               // JSON-LD only
-	ID                  ID         `json:"id,omitempty" access:"ro"`                  // Identifier
-	Title               string     `json:"title" access:"rw"`                         // Photo title
-	Comment             string     `json:"comment,omitempty" access:"rw"`             // Photo comment
-	Place               string     `json:"place,omitempty" access:"rw"`               // Photo location
-	Album               string     `json:"album" access:"rw"`                         // Album IRI
-	Owner               string     `json:"owner,omitempty" access:"ro"`               // Owner IRI
-	Image               string     `json:"image,omitempty" access:"ro"`               // Image URL
-	ImageSmallThumbnail string     `json:"imageSmallThumbnail,omitempty" access:"ro"` // Small thumbnail URL
-	TakenAt             time.Time  `json:"takenAt,omitempty" access:"ro"`             // Date taken
-	Visibility          Visibility `json:"visibility,omitempty" access:"rw"`          // Visibility level
-	ParentVisibility    string     `json:"parentVisibility,omitempty" access:"ro"`    // Parent visibility
-	FinalVisibility     Visibility `json:"finalVisibility,omitempty" access:"ro"`     // Effective visibility
-	CreatedAt           time.Time  `json:"createdAt" access:"ro"`                     // Creation timestamp
-	UpdatedAt           time.Time  `json:"updatedAt,omitempty" access:"ro"`           // Update timestamp
-	File                string     `json:"file,omitempty" access:"wo"`                // Image file data
+	Context             Context             `json:"@context,omitempty" access:"rw"`                         // JSON-LD only
+	_ID                 ID                  `json:"@id,omitempty" access:"ro"`                              // JSON-LD only
+	Type                string              `json:"@type,omitempty" access:"rw"`                            // JSON-LD only
+	ID                  ID                  `json:"id,omitempty" access:"ro" render:"default"`              // Identifier
+	Title               string              `json:"title" access:"rw" render:"default"`                     // Photo title
+	Comment             string              `json:"comment,omitempty" access:"rw"`                          // Photo comment
+	Place               string              `json:"place,omitempty" access:"rw"`                            // Photo location
+	Album               string              `json:"album" access:"rw" render:"default"`                     // Album IRI
+	Owner               string              `json:"owner,omitempty" access:"ro"`                            // Owner IRI
+	Image               string              `json:"image,omitempty" access:"ro"`                            // Image URL
+	ImageSmallThumbnail string              `json:"imageSmallThumbnail,omitempty" access:"ro"`              // Small thumbnail URL
+	TakenAt             Nullable[time.Time] `json:"takenAt,omitzero" access:"ro" render:"default"`          // Date taken
+	Visibility          Visibility          `json:"visibility,omitempty" access:"rw"`                       // Visibility level
+	ParentVisibility    string              `json:"parentVisibility,omitempty" access:"ro"`                 // Parent visibility
+	FinalVisibility     Visibility          `json:"finalVisibility,omitempty" access:"ro" render:"default"` // Effective visibility
+	CreatedAt           time.Time           `json:"createdAt,omitzero" access:"ro"`                         // Creation timestamp
+	UpdatedAt           Nullable[time.Time] `json:"updatedAt,omitzero" access:"ro"`                         // Update timestamp
+	File                string              `json:"file,omitempty" access:"wo"`                             // Image file data
 
 }
 
@@ -54,7 +54,7 @@ func (p *Photo) Validate() error {
 		errs = append(errs, "photo album IRI is required")
 	}
 	// takenAt type string or null, format date-time; see components.schemas.Photo-photo.write.properties.takenAt
-	if p.TakenAt.IsZero() {
+	if t, ok := p.TakenAt.Get(); ok && t.IsZero() {
 		errs = append(errs, "invalid takenAt: must be a valid date-time or null")
 	}
 	validateVisibility(p, &errs)
@@ -101,6 +101,8 @@ func replaceNonPrintableElements(data interface{}) (interface{}, error) {
 
 // PrintError prints the request headers, request body, response headers, response body, and error struct or raw error text from the httpClient struct to stdout.
 func (c *koiClient) PrintError() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	fmt.Println("Last request URL:\n   ", *c.lastRequestURL)
 	decoded, _ := url.QueryUnescape(*c.lastRequestURL)
 	fmt.Println("    ", decoded)
@@ -0,0 +1,619 @@
+package koiApi
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SearchIndex holds items together with their data, tags and collection path for local searching.
+type SearchIndex struct {
+	docs  []*searchDoc
+	Built time.Time
+}
+
+type searchDoc struct {
+	item *Item
+	data []*Datum
+	tags []string
+	path string
+}
+
+// SearchResult is an item matching a query, with its score and the matched text.
+type SearchResult struct {
+	Item       *Item
+	Score      float64
+	Highlights []Highlight
+}
+
+// Highlight is a matched piece of text; Text[Start:End] is the match.
+type Highlight struct {
+	Field string // name, tag, collection or the datum label
+	Text  string
+	Start int
+	End   int
+}
+
+// Marked returns the highlight text with the match wrapped in pre and post.
+func (h Highlight) Marked(pre, post string) string {
+	if h.Start >= h.End {
+		return h.Text
+	}
+	return h.Text[:h.Start] + pre + h.Text[h.Start:h.End] + post + h.Text[h.End:]
+}
+
+// SearchResults is a ranked list of results.
+type SearchResults []*SearchResult
+
+// Items returns the matched items in rank order.
+func (r SearchResults) Items() []*Item {
+	items := make([]*Item, len(r))
+	for i, res := range r {
+		items[i] = res.Item
+	}
+	return items
+}
+
+// Summary
+func (r *SearchResult) Summary() string {
+	var marks []string
+	for _, h := range r.Highlights {
+		marks = append(marks, fmt.Sprintf("%s: %s", h.Field, h.Marked("[", "]")))
+	}
+	return fmt.Sprintf("%6.1f %s  %s", r.Score, r.Item.Summary(), strings.Join(marks, "; "))
+}
+
+// itemCatalog is every item with its data, tags and collection, loaded in bulk.
+type itemCatalog struct {
+	items       []*Item
+	data        map[string][]*Datum // item IRI -> data sorted by position
+	tags        map[string][]string // item IRI -> tag labels
+	collections []*Collection
+	paths       map[string]string // collection IRI -> title path
+}
+
+// loadItemCatalog fetches items, data, collections and tags through c, which may be the live client or a
+// CachedClient. Data and tags are fetched in bulk rather than once per item. Tags are left out when c cannot
+// list them, e.g. a cache.
+func loadItemCatalog(c Client) (*itemCatalog, error) {
+	cat := &itemCatalog{data: map[string][]*Datum{}, tags: map[string][]string{}}
+	if err := c.listResources(baseObjPath(&Item{}), &cat.items); err != nil {
+		return nil, fmt.Errorf("listing items: %w", err)
+	}
+	var data []*Datum
+	if err := c.listResources(baseObjPath(&Datum{}), &data); err != nil {
+		return nil, fmt.Errorf("listing data: %w", err)
+	}
+	if err := c.listResources(baseObjPath(&Collection{}), &cat.collections); err != nil {
+		return nil, fmt.Errorf("listing collections: %w", err)
+	}
+	var tags []*Tag
+	if err := c.listResources(baseObjPath(&Tag{}), &tags); err != nil {
+		return nil, fmt.Errorf("listing tags: %w", err)
+	}
+
+	for _, d := range data {
+		if d.Item != "" {
+			cat.data[d.Item] = append(cat.data[d.Item], d)
+		}
+	}
+	for _, itemData := range cat.data {
+		sort.SliceStable(itemData, func(i, j int) bool { return itemData[i].Position < itemData[j].Position })
+	}
+	for _, t := range tags {
+		var tagged []*Item
+		err := c.listResources(t.IRI()+"/items", &tagged)
+		if errors.Is(err, ErrNotCached) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("listing items tagged %s: %w", t.Label, err)
+		}
+		for _, i := range tagged {
+			cat.tags[i.IRI()] = append(cat.tags[i.IRI()], t.Label)
+		}
+	}
+	cat.paths = collectionPaths(cat.collections)
+	return cat, nil
+}
+
+// BuildSearchIndex loads every item with its data, tags and collection path through c, which may be the live
+// client or a CachedClient.
+func BuildSearchIndex(c Client) (*SearchIndex, error) {
+	cat, err := loadItemCatalog(c)
+	if err != nil {
+		return nil, err
+	}
+	idx := &SearchIndex{Built: time.Now()}
+	for _, item := range cat.items {
+		idx.docs = append(idx.docs, &searchDoc{
+			item: item,
+			data: cat.data[item.IRI()],
+			tags: cat.tags[item.IRI()],
+			path: cat.paths[item.Collection],
+		})
+	}
+	return idx, nil
+}
+
+// Len returns the number of indexed items.
+func (idx *SearchIndex) Len() int {
+	return len(idx.docs)
+}
+
+// Search runs a query against the index and returns matching items, best first.
+//
+// A query is a list of terms joined by AND (the default), OR and NOT, with parentheses for grouping:
+//
+//	tolkien                         free text in the name, data values, tags or collection path
+//	"lord of the rings"             quoted free text
+//	name:hobbit                     item name contains
+//	tag:signed                      item has the tag
+//	collection:books                collection path contains
+//	label:author                    item has a non-empty datum with that label
+//	label:author contains tolkien   datum comparison; operators are contains, =, !=, <, <=, >, >=
+//	price<20                        shorthand for label:price < 20
+//
+// Number, rating and price data compare numerically and date data compare as dates; other types compare
+// as case-insensitive text.
+func (idx *SearchIndex) Search(query string) (SearchResults, error) {
+	node, err := parseSearchQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	var results SearchResults
+	for _, doc := range idx.docs {
+		ok, score, highlights := node.match(doc)
+		if ok {
+			results = append(results, &SearchResult{Item: doc.item, Score: score, Highlights: highlights})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return strings.ToLower(results[i].Item.Name) < strings.ToLower(results[j].Item.Name)
+	})
+	return results, nil
+}
+
+// searchNode is a parsed query expression.
+type searchNode interface {
+	match(doc *searchDoc) (bool, float64, []Highlight)
+}
+
+type andNode []searchNode
+type orNode []searchNode
+type notNode struct{ node searchNode }
+type textNode struct{ text string }
+type tagNode struct{ tag string }
+type nameNode struct{ text string }
+type pathNode struct{ text string }
+type datumNode struct{ label, op, value string }
+
+func (n andNode) match(doc *searchDoc) (bool, float64, []Highlight) {
+	var total float64
+	var all []Highlight
+	for _, child := range n {
+		ok, score, hl := child.match(doc)
+		if !ok {
+			return false, 0, nil
+		}
+		total += score
+		all = append(all, hl...)
+	}
+	return true, total, all
+}
+
+func (n orNode) match(doc *searchDoc) (bool, float64, []Highlight) {
+	matched := false
+	var total float64
+	var all []Highlight
+	for _, child := range n {
+		if ok, score, hl := child.match(doc); ok {
+			matched = true
+			total += score
+			all = append(all, hl...)
+		}
+	}
+	return matched, total, all
+}
+
+func (n notNode) match(doc *searchDoc) (bool, float64, []Highlight) {
+	ok, _, _ := n.node.match(doc)
+	return !ok, 0, nil
+}
+
+// Free text weighs name matches above tags, tags above data and data above the collection path.
+func (n textNode) match(doc *searchDoc) (bool, float64, []Highlight) {
+	var score float64
+	var hl []Highlight
+	if h, ok := highlight("name", doc.item.Name, n.text); ok {
+		score += 3
+		hl = append(hl, h)
+	}
+	for _, t := range doc.tags {
+		if h, ok := highlight("tag", t, n.text); ok {
+			score += 2
+			hl = append(hl, h)
+		}
+	}
+	for _, d := range doc.data {
+		if h, ok := highlight(d.Label, d.Value, n.text); ok {
+			score++
+			hl = append(hl, h)
+		}
+	}
+	if h, ok := highlight("collection", doc.path, n.text); ok {
+		score += 0.5
+		hl = append(hl, h)
+	}
+	return score > 0, score, hl
+}
+
+func (n tagNode) match(doc *searchDoc) (bool, float64, []Highlight) {
+	for _, t := range doc.tags {
+		if strings.EqualFold(t, n.tag) {
+			return true, 2, []Highlight{{Field: "tag", Text: t, End: len(t)}}
+		}
+	}
+	return false, 0, nil
+}
+
+func (n nameNode) match(doc *searchDoc) (bool, float64, []Highlight) {
+	if h, ok := highlight("name", doc.item.Name, n.text); ok {
+		return true, 3, []Highlight{h}
+	}
+	return false, 0, nil
+}
+
+func (n pathNode) match(doc *searchDoc) (bool, float64, []Highlight) {
+	if h, ok := highlight("collection", doc.path, n.text); ok {
+		return true, 1, []Highlight{h}
+	}
+	return false, 0, nil
+}
+
+func (n datumNode) match(doc *searchDoc) (bool, float64, []Highlight) {
+	label := toLowerNoSpaces(n.label)
+	for _, d := range doc.data {
+		if toLowerNoSpaces(d.Label) != label {
+			continue
+		}
+		if n.op == "" {
+			if d.Value != "" {
+				return true, 1, []Highlight{{Field: d.Label, Text: d.Value}}
+			}
+			continue
+		}
+		if compareDatum(d, n.op, n.value) {
+			h := Highlight{Field: d.Label, Text: d.Value, End: len(d.Value)}
+			if n.op == "contains" {
+				h, _ = highlight(d.Label, d.Value, n.value)
+			}
+			return true, 1, []Highlight{h}
+		}
+	}
+	return false, 0, nil
+}
+
+// highlight finds text in s, case-insensitively. Windows of s are compared rune by rune rather than searching
+// a lowercased copy, whose byte offsets can differ from those of s.
+func highlight(field, s, text string) (Highlight, bool) {
+	n := utf8.RuneCountInString(text)
+	if n == 0 {
+		return Highlight{}, false
+	}
+	for start := 0; start < len(s); {
+		end := start
+		for i := 0; i < n && end < len(s); i++ {
+			_, size := utf8.DecodeRuneInString(s[end:])
+			end += size
+		}
+		if strings.EqualFold(s[start:end], text) {
+			return Highlight{Field: field, Text: s, Start: start, End: end}, true
+		}
+		_, size := utf8.DecodeRuneInString(s[start:])
+		start += size
+	}
+	return Highlight{}, false
+}
+
+// compareDatum compares a datum value against value according to the datum type.
+func compareDatum(d *Datum, op, value string) bool {
+	if op == "contains" {
+		return strings.Contains(strings.ToLower(d.Value), strings.ToLower(value))
+	}
+	cmp, ok := 0, false
+	switch d.DatumType {
+	case DatumTypeNumber, DatumTypeRating, DatumTypePrice:
+		a, err1 := strconv.ParseFloat(strings.TrimSpace(d.Value), 64)
+		b, err2 := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err1 == nil && err2 == nil {
+			cmp, ok = compareOrdered(a, b), true
+		}
+	case DatumTypeDate:
+		a, err1 := parseSearchDate(d.Value)
+		b, err2 := parseSearchDate(value)
+		if err1 == nil && err2 == nil {
+			cmp, ok = a.Compare(b), true
+		}
+	}
+	if !ok {
+		cmp = strings.Compare(strings.ToLower(d.Value), strings.ToLower(value))
+	}
+	switch op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+func compareOrdered(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// parseSearchDate accepts ISO dates, date-times and bare years.
+func parseSearchDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{"2006-01-02", time.RFC3339, "2006-01-02T15:04:05", "2006-01", "2006"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date: %s", s)
+}
+
+// searchToken is a lexical token of a query; quoted strings are never keywords or operators.
+type searchToken struct {
+	text   string
+	quoted bool
+	op     bool
+}
+
+func (t searchToken) is(word string) bool {
+	return !t.quoted && strings.EqualFold(t.text, word)
+}
+
+func tokenizeSearch(q string) ([]searchToken, error) {
+	var tokens []searchToken
+	r := []rune(q)
+	for i := 0; i < len(r); {
+		switch c := r[i]; {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(' || c == ')' || c == ':':
+			tokens = append(tokens, searchToken{text: string(c), op: true})
+			i++
+		case c == '<' || c == '>' || c == '!' || c == '=':
+			op := string(c)
+			if i+1 < len(r) && r[i+1] == '=' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, fmt.Errorf("unexpected '!' at position %d", i)
+			}
+			tokens = append(tokens, searchToken{text: op, op: true})
+			i += len(op)
+		case c == '"':
+			end := i + 1
+			for end < len(r) && r[end] != '"' {
+				end++
+			}
+			if end == len(r) {
+				return nil, fmt.Errorf("unterminated quote at position %d", i)
+			}
+			tokens = append(tokens, searchToken{text: string(r[i+1 : end]), quoted: true})
+			i = end + 1
+		default:
+			end := i
+			for end < len(r) && !unicode.IsSpace(r[end]) && !strings.ContainsRune(`()":<>!=`, r[end]) {
+				end++
+			}
+			tokens = append(tokens, searchToken{text: string(r[i:end])})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+type searchParser struct {
+	tokens []searchToken
+	pos    int
+}
+
+func parseSearchQuery(q string) (searchNode, error) {
+	tokens, err := tokenizeSearch(q)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return andNode{}, nil
+	}
+	p := &searchParser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in query", p.tokens[p.pos].text)
+	}
+	return node, nil
+}
+
+func (p *searchParser) peek() (searchToken, bool) {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos], true
+	}
+	return searchToken{}, false
+}
+
+func (p *searchParser) next() (searchToken, bool) {
+	t, ok := p.peek()
+	if ok {
+		p.pos++
+	}
+	return t, ok
+}
+
+func (p *searchParser) parseOr() (searchNode, error) {
+	node, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	or := orNode{node}
+	for {
+		t, ok := p.peek()
+		if !ok || !t.is("or") {
+			break
+		}
+		p.pos++
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, node)
+	}
+	if len(or) == 1 {
+		return or[0], nil
+	}
+	return or, nil
+}
+
+func (p *searchParser) parseAnd() (searchNode, error) {
+	var and andNode
+	for {
+		t, ok := p.peek()
+		if !ok || t.is("or") || (t.op && t.text == ")") {
+			break
+		}
+		if t.is("and") {
+			p.pos++
+			if next, ok := p.peek(); !ok || next.is("or") || next.is("and") || (next.op && next.text == ")") {
+				return nil, fmt.Errorf("AND without a right-hand term in query")
+			}
+			continue
+		}
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, node)
+	}
+	if len(and) == 0 {
+		return nil, fmt.Errorf("empty expression in query")
+	}
+	if len(and) == 1 {
+		return and[0], nil
+	}
+	return and, nil
+}
+
+func (p *searchParser) parseUnary() (searchNode, error) {
+	t, ok := p.next()
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("missing term after NOT in query")
+	case t.is("not"):
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{node}, nil
+	case t.op && t.text == "(":
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing, ok := p.next(); !ok || !closing.op || closing.text != ")" {
+			return nil, fmt.Errorf("missing ) in query")
+		}
+		return node, nil
+	case t.op:
+		return nil, fmt.Errorf("unexpected %q in query", t.text)
+	}
+
+	next, more := p.peek()
+	if !more || t.quoted {
+		return textNode{t.text}, nil
+	}
+	if next.op && next.text == ":" {
+		p.pos++
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		switch strings.ToLower(t.text) {
+		case "tag":
+			return tagNode{value}, nil
+		case "name":
+			return nameNode{value}, nil
+		case "collection":
+			return pathNode{value}, nil
+		case "label":
+			node := datumNode{label: value}
+			if op, ok := p.operator(); ok {
+				if node.value, err = p.value(); err != nil {
+					return nil, err
+				}
+				node.op = op
+			}
+			return node, nil
+		}
+		return nil, fmt.Errorf("unknown search field: %s", t.text)
+	}
+	if op, ok := p.operator(); ok {
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		return datumNode{label: t.text, op: op, value: value}, nil
+	}
+	return textNode{t.text}, nil
+}
+
+// operator consumes a comparison operator if one is next.
+func (p *searchParser) operator() (string, bool) {
+	t, ok := p.peek()
+	if !ok {
+		return "", false
+	}
+	if t.is("contains") {
+		p.pos++
+		return "contains", true
+	}
+	if t.op && t.text != "(" && t.text != ")" && t.text != ":" {
+		p.pos++
+		return t.text, true
+	}
+	return "", false
+}
+
+func (p *searchParser) value() (string, error) {
+	t, ok := p.next()
+	if !ok || t.op {
+		return "", fmt.Errorf("missing value in query")
+	}
+	return t.text, nil
+}
@@ -0,0 +1,156 @@
+package koiApi
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf8"
+)
+
+func testSearchIndex() *SearchIndex {
+	return &SearchIndex{docs: []*searchDoc{
+		{
+			item: &Item{ID: "1", Name: "The Hobbit"},
+			data: []*Datum{
+				{Label: "Author", Value: "J. R. R. Tolkien", DatumType: DatumTypeText},
+				{Label: "Price", Value: "12.5", DatumType: DatumTypePrice},
+				{Label: "Published", Value: "1937-09-21", DatumType: DatumTypeDate},
+			},
+			tags: []string{"fantasy", "signed"},
+			path: "Books/Fantasy",
+		},
+		{
+			item: &Item{ID: "2", Name: "Dune"},
+			data: []*Datum{
+				{Label: "Author", Value: "Frank Herbert", DatumType: DatumTypeText},
+				{Label: "Price", Value: "30", DatumType: DatumTypePrice},
+				{Label: "Published", Value: "1965", DatumType: DatumTypeDate},
+			},
+			tags: []string{"science fiction"},
+			path: "Books/Science fiction",
+		},
+		{
+			item: &Item{ID: "3", Name: "Fantasy stamps"},
+			path: "Stamps",
+		},
+	}}
+}
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  searchNode
+	}{
+		{"tolkien", textNode{"tolkien"}},
+		{`"lord of the rings"`, textNode{"lord of the rings"}},
+		{"a b", andNode{textNode{"a"}, textNode{"b"}}},
+		{"a AND b", andNode{textNode{"a"}, textNode{"b"}}},
+		{"a or b", orNode{textNode{"a"}, textNode{"b"}}},
+		{"a b OR c", orNode{andNode{textNode{"a"}, textNode{"b"}}, textNode{"c"}}},
+		{"a (b OR c)", andNode{textNode{"a"}, orNode{textNode{"b"}, textNode{"c"}}}},
+		{"NOT tag:signed", notNode{tagNode{"signed"}}},
+		{"name:hobbit", nameNode{"hobbit"}},
+		{"collection:books", pathNode{"books"}},
+		{"label:author", datumNode{label: "author"}},
+		{"label:author contains tolkien", datumNode{label: "author", op: "contains", value: "tolkien"}},
+		{"price<20", datumNode{label: "price", op: "<", value: "20"}},
+		{"price >= 20", datumNode{label: "price", op: ">=", value: "20"}},
+		{"price!=20", datumNode{label: "price", op: "!=", value: "20"}},
+		{`"or"`, textNode{"or"}},
+	}
+	for _, tt := range tests {
+		got, err := parseSearchQuery(tt.query)
+		if err != nil {
+			t.Errorf("parseSearchQuery(%q): %v", tt.query, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseSearchQuery(%q) = %#v, want %#v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParseSearchQueryErrors(t *testing.T) {
+	for _, query := range []string{`"open`, "(a", "a)", "price<", "color:red", "a !b", "OR a", "()", "dune NOT", "NOT", "dune AND", "a AND OR b", "NOT NOT"} {
+		if _, err := parseSearchQuery(query); err == nil {
+			t.Errorf("parseSearchQuery(%q) succeeded, want an error", query)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []ID
+	}{
+		{"fantasy", []ID{"3", "1"}}, // A name match (3) beats a tag (2) and path (0.5) match
+		{"tolkien", []ID{"1"}},
+		{"tag:signed", []ID{"1"}},
+		{"NOT tag:signed", []ID{"2", "3"}},
+		{"price<20", []ID{"1"}},
+		{"price > 20", []ID{"2"}},
+		{"published < 1950", []ID{"1"}},
+		{"label:author contains herbert", []ID{"2"}},
+		{"label:author", []ID{"2", "1"}}, // Equal scores sort by name
+		{"collection:books (dune OR hobbit)", []ID{"2", "1"}},
+	}
+	idx := testSearchIndex()
+	for _, tt := range tests {
+		results, err := idx.Search(tt.query)
+		if err != nil {
+			t.Errorf("Search(%q): %v", tt.query, err)
+			continue
+		}
+		var got []ID
+		for _, item := range results.Items() {
+			got = append(got, item.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSearchScore(t *testing.T) {
+	results, err := testSearchIndex().Search("fantasy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].Score != 3 || results[1].Score != 2.5 {
+		t.Fatalf("unexpected scores: %v", results)
+	}
+	if got := results[1].Highlights[0].Marked("[", "]"); got != "[fantasy]" {
+		t.Errorf("tag highlight = %q", got)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		s, text, want string
+	}{
+		{"The Hobbit", "hobbit", "The [Hobbit]"},
+		{"Ⱥx", "x", "Ⱥ[x]"},                // Ⱥ lowercases to a longer encoding
+		{"İstanbul", "stan", "İ[stan]bul"}, // İ lowercases to a shorter one
+		{"Café Crème", "CRÈME", "Café [Crème]"},
+		{"Straße", "STRASSE", ""}, // Only simple case folding applies
+	}
+	for _, tt := range tests {
+		h, ok := highlight("name", tt.s, tt.text)
+		if tt.want == "" {
+			if ok {
+				t.Errorf("highlight(%q, %q) matched %+v", tt.s, tt.text, h)
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("highlight(%q, %q) did not match", tt.s, tt.text)
+			continue
+		}
+		got := h.Marked("[", "]")
+		if got != tt.want || !utf8.ValidString(got) {
+			t.Errorf("highlight(%q, %q) marked = %q, want %q", tt.s, tt.text, got, tt.want)
+		}
+	}
+	if _, ok := highlight("name", "anything", ""); ok {
+		t.Error("empty text matched")
+	}
+}
@@ -0,0 +1,435 @@
+package koiApi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Rates is an offline exchange rate table: one unit of a currency is worth Rates[currency] units of Base.
+type Rates struct {
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+// LoadRates reads a rates table from a JSON file such as {"base": "EUR", "rates": {"USD": 0.92, "GBP": 1.17}}.
+func LoadRates(filename string) (*Rates, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading rates %s: %w", filename, err)
+	}
+	var r Rates
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("decoding rates %s: %w", filename, err)
+	}
+	// Codes are stored upper-case, the way Convert looks them up.
+	rates := make(map[string]float64, len(r.Rates))
+	for code, rate := range r.Rates {
+		upper := strings.ToUpper(code)
+		if _, dup := rates[upper]; dup {
+			return nil, fmt.Errorf("rates %s: %s is listed twice: %w", filename, upper, ErrInvalidInput)
+		}
+		rates[upper] = rate
+	}
+	r.Base, r.Rates = strings.ToUpper(r.Base), rates
+	return &r, r.Validate()
+}
+
+// Validate
+func (r *Rates) Validate() error {
+	var errs []string
+	if !validateCurrency(r.Base) {
+		errs = append(errs, fmt.Sprintf("invalid base currency code: %s", r.Base))
+	}
+	for code, rate := range r.Rates {
+		if !validateCurrency(code) {
+			errs = append(errs, fmt.Sprintf("invalid currency code: %s", code))
+		}
+		if rate <= 0 {
+			errs = append(errs, fmt.Sprintf("rate for %s must be positive", code))
+		}
+	}
+	return validationErrors(&errs)
+}
+
+// Convert converts amount in currency to the base currency.
+func (r *Rates) Convert(amount float64, currency string) (float64, bool) {
+	if strings.EqualFold(currency, r.Base) {
+		return amount, true
+	}
+	rate, ok := r.Rates[strings.ToUpper(currency)]
+	return amount * rate, ok
+}
+
+// ValueStats aggregates price values in one currency.
+type ValueStats struct {
+	Currency string  `json:"currency"`
+	Count    int     `json:"count"` // Number of priced items or wishes
+	Total    float64 `json:"total"`
+	Average  float64 `json:"average"`
+	Min      float64 `json:"min"`
+	Max      float64 `json:"max"`
+}
+
+func (v *ValueStats) add(amount float64) {
+	if v.Count == 0 || amount < v.Min {
+		v.Min = amount
+	}
+	if v.Count == 0 || amount > v.Max {
+		v.Max = amount
+	}
+	v.Count++
+	v.Total += amount
+	v.Average = v.Total / float64(v.Count)
+}
+
+func (v *ValueStats) merge(o *ValueStats) {
+	if o == nil || o.Count == 0 {
+		return
+	}
+	if v.Count == 0 || o.Min < v.Min {
+		v.Min = o.Min
+	}
+	if v.Count == 0 || o.Max > v.Max {
+		v.Max = o.Max
+	}
+	v.Count += o.Count
+	v.Total += o.Total
+	v.Average = v.Total / float64(v.Count)
+}
+
+// Summary
+func (v *ValueStats) Summary() string {
+	return fmt.Sprintf("%s total %.2f  avg %.2f  min %.2f  max %.2f  (%d priced)", v.Currency, v.Total, v.Average, v.Min, v.Max, v.Count)
+}
+
+// CollectionStats holds statistics for a collection including all of its descendants.
+type CollectionStats struct {
+	IRI           string                        `json:"iri"`
+	Title         string                        `json:"title"`
+	Path          string                        `json:"path"`
+	Items         int                           `json:"items"`
+	Quantity      int                           `json:"quantity"`
+	Values        map[string]*ValueStats        `json:"values,omitempty"`        // By currency
+	BaseValue     *ValueStats                   `json:"baseValue,omitempty"`     // All values converted to the rates' base currency
+	Unconverted   []string                      `json:"unconverted,omitempty"`   // Currencies missing from the rates table
+	ValueByTag    map[string]map[string]float64 `json:"valueByTag,omitempty"`    // Tag -> currency -> total
+	AddedPerMonth map[string]int                `json:"addedPerMonth,omitempty"` // "2006-01" -> items created
+	Children      []*CollectionStats            `json:"children,omitempty"`
+}
+
+// Statistics computes statistics for every root collection through c, which may be the live client or a
+// CachedClient. Price data are summed as recorded, without multiplying by the item quantity. When rates is
+// non-nil, values are also converted to its base currency.
+func Statistics(c Client, rates *Rates) ([]*CollectionStats, error) {
+	cat, err := loadItemCatalog(c)
+	if err != nil {
+		return nil, err
+	}
+	var roots []*CollectionStats
+	for _, col := range cat.collections {
+		if col.Parent == "" {
+			roots = append(roots, cat.collectionStats(col, rates))
+		}
+	}
+	sort.Slice(roots, func(i, j int) bool { return roots[i].Path < roots[j].Path })
+	return roots, nil
+}
+
+// CollectionStatistics computes statistics for one collection and its descendants.
+func CollectionStatistics(c Client, root *Collection, rates *Rates) (*CollectionStats, error) {
+	cat, err := loadItemCatalog(c)
+	if err != nil {
+		return nil, err
+	}
+	for _, col := range cat.collections {
+		if col.IRI() == root.IRI() {
+			return cat.collectionStats(col, rates), nil
+		}
+	}
+	return nil, fmt.Errorf("collection %s: %w", root.IRI(), ErrNotFound)
+}
+
+func (cat *itemCatalog) collectionStats(col *Collection, rates *Rates) *CollectionStats {
+	s := &CollectionStats{
+		IRI:           col.IRI(),
+		Title:         col.Title,
+		Path:          cat.paths[col.IRI()],
+		Values:        map[string]*ValueStats{},
+		ValueByTag:    map[string]map[string]float64{},
+		AddedPerMonth: map[string]int{},
+	}
+	if rates != nil {
+		s.BaseValue = &ValueStats{Currency: rates.Base}
+	}
+	unconverted := map[string]bool{}
+
+	for _, item := range cat.items {
+		if item.Collection != col.IRI() {
+			continue
+		}
+		s.Items++
+		s.Quantity += item.Quantity
+		if !item.CreatedAt.IsZero() {
+			s.AddedPerMonth[item.CreatedAt.Format("2006-01")]++
+		}
+		for _, d := range cat.data[item.IRI()] {
+			if d.DatumType != DatumTypePrice {
+				continue
+			}
+			currency, amount, ok := addPrice(s.Values, s.BaseValue, unconverted, rates, d.Value, d.Currency)
+			if !ok {
+				continue
+			}
+			for _, tag := range cat.tags[item.IRI()] {
+				if s.ValueByTag[tag] == nil {
+					s.ValueByTag[tag] = map[string]float64{}
+				}
+				s.ValueByTag[tag][currency] += amount
+			}
+		}
+	}
+
+	for _, child := range cat.collections {
+		if child.Parent != col.IRI() {
+			continue
+		}
+		cs := cat.collectionStats(child, rates)
+		s.Children = append(s.Children, cs)
+		s.Items += cs.Items
+		s.Quantity += cs.Quantity
+		for currency, v := range cs.Values {
+			if s.Values[currency] == nil {
+				s.Values[currency] = &ValueStats{Currency: currency}
+			}
+			s.Values[currency].merge(v)
+		}
+		if s.BaseValue != nil {
+			s.BaseValue.merge(cs.BaseValue)
+		}
+		for _, currency := range cs.Unconverted {
+			unconverted[currency] = true
+		}
+		for tag, byCurrency := range cs.ValueByTag {
+			if s.ValueByTag[tag] == nil {
+				s.ValueByTag[tag] = map[string]float64{}
+			}
+			for currency, total := range byCurrency {
+				s.ValueByTag[tag][currency] += total
+			}
+		}
+		for month, n := range cs.AddedPerMonth {
+			s.AddedPerMonth[month] += n
+		}
+	}
+	sort.Slice(s.Children, func(i, j int) bool { return s.Children[i].Title < s.Children[j].Title })
+	for currency := range unconverted {
+		s.Unconverted = append(s.Unconverted, currency)
+	}
+	sort.Strings(s.Unconverted)
+	return s
+}
+
+// addPrice parses a price and adds it to values under its currency, defaulting to the machine's, and to base
+// when it can be converted with rates. Currencies missing from rates are recorded in unconverted.
+func addPrice(values map[string]*ValueStats, base *ValueStats, unconverted map[string]bool, rates *Rates, value, currency string) (string, float64, bool) {
+	amount, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || math.IsNaN(amount) {
+		return "", 0, false
+	}
+	currency = strings.ToUpper(currency)
+	if currency == "" {
+		currency = getDefaultCurrency()
+	}
+	if values[currency] == nil {
+		values[currency] = &ValueStats{Currency: currency}
+	}
+	values[currency].add(amount)
+	if rates != nil {
+		if converted, ok := rates.Convert(amount, currency); ok {
+			base.add(converted)
+		} else {
+			unconverted[currency] = true
+		}
+	}
+	return currency, amount, true
+}
+
+// Summary
+func (s *CollectionStats) Summary() string {
+	return fmt.Sprintf("%-40s %6d items %6d qty", s.Title, s.Items, s.Quantity)
+}
+
+// JSON renders the statistics, including children, as indented JSON.
+func (s *CollectionStats) JSON() (string, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	return string(data), err
+}
+
+// Text renders the statistics as an indented text report; pass an indent level to nest it.
+func (s *CollectionStats) Text(opt ...int) string {
+	indent := getArg(0, opt)
+	var sb strings.Builder
+	pad := indentChars(indent)
+	fmt.Fprintf(&sb, "%s%s\n", pad, s.Summary())
+	for _, currency := range sortedKeys(s.Values) {
+		fmt.Fprintf(&sb, "%s    %s\n", pad, s.Values[currency].Summary())
+	}
+	if s.BaseValue != nil && s.BaseValue.Count > 0 {
+		fmt.Fprintf(&sb, "%s    = %s\n", pad, s.BaseValue.Summary())
+	}
+	if len(s.Unconverted) > 0 {
+		fmt.Fprintf(&sb, "%s    no rate for: %s\n", pad, strings.Join(s.Unconverted, ", "))
+	}
+	for _, tag := range sortedKeys(s.ValueByTag) {
+		var parts []string
+		for _, currency := range sortedKeys(s.ValueByTag[tag]) {
+			parts = append(parts, fmt.Sprintf("%s %.2f", currency, s.ValueByTag[tag][currency]))
+		}
+		fmt.Fprintf(&sb, "%s    tag %-20s %s\n", pad, tag, strings.Join(parts, ", "))
+	}
+	if len(s.AddedPerMonth) > 0 {
+		var parts []string
+		for _, month := range sortedKeys(s.AddedPerMonth) {
+			parts = append(parts, fmt.Sprintf("%s: %d", month, s.AddedPerMonth[month]))
+		}
+		fmt.Fprintf(&sb, "%s    added %s\n", pad, strings.Join(parts, ", "))
+	}
+	for _, child := range s.Children {
+		sb.WriteString(child.Text(indent + 4))
+	}
+	return sb.String()
+}
+
+// WishlistStats holds the value of the wishes in a wishlist including all of its descendants.
+type WishlistStats struct {
+	IRI         string                 `json:"iri"`
+	Name        string                 `json:"name"`
+	Path        string                 `json:"path"`
+	Wishes      int                    `json:"wishes"`
+	Values      map[string]*ValueStats `json:"values,omitempty"`      // By currency
+	BaseValue   *ValueStats            `json:"baseValue,omitempty"`   // All values converted to the rates' base currency
+	Unconverted []string               `json:"unconverted,omitempty"` // Currencies missing from the rates table
+	Children    []*WishlistStats       `json:"children,omitempty"`
+}
+
+// WishlistStatistics totals wish prices per currency for every root wishlist through c, which may be the live
+// client or a CachedClient. When rates is non-nil, values are also converted to its base currency.
+func WishlistStatistics(c Client, rates *Rates) ([]*WishlistStats, error) {
+	var wishlists []*Wishlist
+	if err := c.listResources(baseObjPath(&Wishlist{}), &wishlists); err != nil {
+		return nil, fmt.Errorf("listing wishlists: %w", err)
+	}
+	var wishes []*Wish
+	if err := c.listResources(baseObjPath(&Wish{}), &wishes); err != nil {
+		return nil, fmt.Errorf("listing wishes: %w", err)
+	}
+	return wishlistStats(wishlists, wishes, rates), nil
+}
+
+func wishlistStats(wishlists []*Wishlist, wishes []*Wish, rates *Rates) []*WishlistStats {
+	byWishlist := map[string][]*Wish{}
+	for _, w := range wishes {
+		byWishlist[w.Wishlist] = append(byWishlist[w.Wishlist], w)
+	}
+	children := map[string][]*Wishlist{}
+	known := map[string]bool{}
+	for _, wl := range wishlists {
+		known[wl.IRI()] = true
+	}
+	var roots []*Wishlist
+	for _, wl := range wishlists {
+		if wl.Parent == "" || !known[wl.Parent] {
+			roots = append(roots, wl)
+		} else {
+			children[wl.Parent] = append(children[wl.Parent], wl)
+		}
+	}
+
+	var build func(wl *Wishlist, path string) *WishlistStats
+	build = func(wl *Wishlist, path string) *WishlistStats {
+		s := &WishlistStats{IRI: wl.IRI(), Name: wl.Name, Path: path, Values: map[string]*ValueStats{}}
+		if rates != nil {
+			s.BaseValue = &ValueStats{Currency: rates.Base}
+		}
+		unconverted := map[string]bool{}
+		for _, w := range byWishlist[wl.IRI()] {
+			s.Wishes++
+			addPrice(s.Values, s.BaseValue, unconverted, rates, w.Price, w.Currency)
+		}
+		for _, child := range children[wl.IRI()] {
+			cs := build(child, path+"/"+child.Name)
+			s.Children = append(s.Children, cs)
+			s.Wishes += cs.Wishes
+			for currency, v := range cs.Values {
+				if s.Values[currency] == nil {
+					s.Values[currency] = &ValueStats{Currency: currency}
+				}
+				s.Values[currency].merge(v)
+			}
+			if s.BaseValue != nil {
+				s.BaseValue.merge(cs.BaseValue)
+			}
+			for _, currency := range cs.Unconverted {
+				unconverted[currency] = true
+			}
+		}
+		sort.Slice(s.Children, func(i, j int) bool { return s.Children[i].Name < s.Children[j].Name })
+		for currency := range unconverted {
+			s.Unconverted = append(s.Unconverted, currency)
+		}
+		sort.Strings(s.Unconverted)
+		return s
+	}
+	stats := make([]*WishlistStats, len(roots))
+	for i, wl := range roots {
+		stats[i] = build(wl, wl.Name)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Path < stats[j].Path })
+	return stats
+}
+
+// Summary
+func (s *WishlistStats) Summary() string {
+	return fmt.Sprintf("%-40s %6d wishes", s.Name, s.Wishes)
+}
+
+// JSON renders the statistics, including children, as indented JSON.
+func (s *WishlistStats) JSON() (string, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	return string(data), err
+}
+
+// Text renders the statistics as an indented text report; pass an indent level to nest it.
+func (s *WishlistStats) Text(opt ...int) string {
+	indent := getArg(0, opt)
+	var sb strings.Builder
+	pad := indentChars(indent)
+	fmt.Fprintf(&sb, "%s%s\n", pad, s.Summary())
+	for _, currency := range sortedKeys(s.Values) {
+		fmt.Fprintf(&sb, "%s    %s\n", pad, s.Values[currency].Summary())
+	}
+	if s.BaseValue != nil && s.BaseValue.Count > 0 {
+		fmt.Fprintf(&sb, "%s    = %s\n", pad, s.BaseValue.Summary())
+	}
+	if len(s.Unconverted) > 0 {
+		fmt.Fprintf(&sb, "%s    no rate for: %s\n", pad, strings.Join(s.Unconverted, ", "))
+	}
+	for _, child := range s.Children {
+		sb.WriteString(child.Text(indent + 4))
+	}
+	return sb.String()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,158 @@
+package koiApi
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testRates = &Rates{Base: "EUR", Rates: map[string]float64{"USD": 0.5}}
+
+func TestRatesConvert(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		currency string
+		want     float64
+		ok       bool
+	}{
+		{10, "EUR", 10, true},
+		{10, "eur", 10, true},
+		{10, "USD", 5, true},
+		{10, "usd", 5, true},
+		{10, "GBP", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := testRates.Convert(tt.amount, tt.currency)
+		if ok != tt.ok || ok && got != tt.want {
+			t.Errorf("Convert(%v, %s) = %v, %v; want %v, %v", tt.amount, tt.currency, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestLoadRates(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		filename := filepath.Join(dir, name)
+		if err := os.WriteFile(filename, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return filename
+	}
+	r, err := LoadRates(write("lower.json", `{"base": "eur", "rates": {"usd": 0.5, "Gbp": 1.2}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Base != "EUR" || r.Rates["USD"] != 0.5 || r.Rates["GBP"] != 1.2 {
+		t.Errorf("LoadRates = %+v, want upper-case codes", r)
+	}
+	if got, ok := r.Convert(10, "usd"); !ok || got != 5 {
+		t.Errorf("Convert(10, usd) = %v, %v", got, ok)
+	}
+	if _, err := LoadRates(write("dup.json", `{"base": "EUR", "rates": {"usd": 0.5, "USD": 0.6}}`)); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("duplicate code: %v", err)
+	}
+	if _, err := LoadRates(write("bad.json", `{"base": "EUR", "rates": {"USD": 0}}`)); err == nil {
+		t.Error("zero rate accepted")
+	}
+}
+
+func TestCollectionStats(t *testing.T) {
+	root := &Collection{ID: "1", Title: "Books"}
+	child := &Collection{ID: "2", Title: "Fantasy", Parent: root.IRI()}
+	hobbit := &Item{ID: "1", Collection: root.IRI(), Quantity: 1, CreatedAt: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)}
+	dune := &Item{ID: "2", Collection: child.IRI(), Quantity: 2, CreatedAt: time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)}
+	stamp := &Item{ID: "3", Collection: child.IRI(), Quantity: 1, CreatedAt: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)}
+	cat := &itemCatalog{
+		items:       []*Item{hobbit, dune, stamp},
+		collections: []*Collection{root, child},
+		data: map[string][]*Datum{
+			hobbit.IRI(): {{DatumType: DatumTypePrice, Value: "10", Currency: "EUR"}},
+			dune.IRI(): {
+				{DatumType: DatumTypePrice, Value: "20", Currency: "usd"},
+				{DatumType: DatumTypeText, Value: "30", Currency: "EUR"}, // Not a price
+			},
+			stamp.IRI(): {
+				{DatumType: DatumTypePrice, Value: "4", Currency: "GBP"},
+				{DatumType: DatumTypePrice, Value: "n/a", Currency: "EUR"},
+			},
+		},
+		tags: map[string][]string{hobbit.IRI(): {"signed"}, dune.IRI(): {"signed"}},
+	}
+	cat.paths = collectionPaths(cat.collections)
+
+	s := cat.collectionStats(root, testRates)
+	if s.Items != 3 || s.Quantity != 4 {
+		t.Errorf("items, quantity = %d, %d; want 3, 4", s.Items, s.Quantity)
+	}
+	want := map[string]ValueStats{
+		"EUR": {Currency: "EUR", Count: 1, Total: 10, Average: 10, Min: 10, Max: 10},
+		"USD": {Currency: "USD", Count: 1, Total: 20, Average: 20, Min: 20, Max: 20},
+		"GBP": {Currency: "GBP", Count: 1, Total: 4, Average: 4, Min: 4, Max: 4},
+	}
+	if len(s.Values) != len(want) {
+		t.Errorf("values = %v, want %v", s.Values, want)
+	}
+	for currency, v := range want {
+		if got := s.Values[currency]; got == nil || *got != v {
+			t.Errorf("values[%s] = %+v, want %+v", currency, got, v)
+		}
+	}
+	// 10 EUR + 20 USD at 0.5; GBP has no rate.
+	if b := s.BaseValue; b.Count != 2 || b.Total != 20 || b.Min != 10 || b.Max != 10 {
+		t.Errorf("base value = %+v", b)
+	}
+	if !reflect.DeepEqual(s.Unconverted, []string{"GBP"}) {
+		t.Errorf("unconverted = %v", s.Unconverted)
+	}
+	if want := map[string]map[string]float64{"signed": {"EUR": 10, "USD": 20}}; !reflect.DeepEqual(s.ValueByTag, want) {
+		t.Errorf("value by tag = %v, want %v", s.ValueByTag, want)
+	}
+	if want := map[string]int{"2024-01": 2, "2024-02": 1}; !reflect.DeepEqual(s.AddedPerMonth, want) {
+		t.Errorf("added per month = %v, want %v", s.AddedPerMonth, want)
+	}
+	if len(s.Children) != 1 || s.Children[0].Path != "Books/Fantasy" || s.Children[0].Items != 2 {
+		t.Errorf("children = %+v", s.Children)
+	}
+}
+
+func TestWishlistStats(t *testing.T) {
+	gifts := &Wishlist{ID: "1", Name: "Gifts"}
+	books := &Wishlist{ID: "2", Name: "Books", Parent: gifts.IRI()}
+	games := &Wishlist{ID: "3", Name: "Games"}
+	wishes := []*Wish{
+		{Name: "Lamp", Wishlist: gifts.IRI(), Price: "30", Currency: "EUR"},
+		{Name: "Atlas", Wishlist: books.IRI(), Price: "12.5", Currency: "EUR"},
+		{Name: "Novel", Wishlist: books.IRI(), Price: "7.5", Currency: "USD"},
+		{Name: "Poster", Wishlist: books.IRI(), Price: "", Currency: "EUR"}, // Unpriced
+		{Name: "Chess", Wishlist: games.IRI(), Price: "40", Currency: "CHF"},
+	}
+
+	stats := wishlistStats([]*Wishlist{books, games, gifts}, wishes, testRates)
+	if len(stats) != 2 || stats[0].Name != "Games" || stats[1].Name != "Gifts" {
+		t.Fatalf("roots = %+v", stats)
+	}
+	g := stats[1]
+	if g.Wishes != 4 {
+		t.Errorf("wishes = %d, want 4", g.Wishes)
+	}
+	if eur := g.Values["EUR"]; eur == nil || eur.Count != 2 || eur.Total != 42.5 || eur.Min != 12.5 || eur.Max != 30 {
+		t.Errorf("EUR values = %+v", eur)
+	}
+	if usd := g.Values["USD"]; usd == nil || usd.Total != 7.5 {
+		t.Errorf("USD values = %+v", usd)
+	}
+	// 30 + 12.5 EUR and 7.5 USD at 0.5.
+	if b := g.BaseValue; b.Count != 3 || math.Abs(b.Total-46.25) > 1e-9 {
+		t.Errorf("base value = %+v", b)
+	}
+	if len(g.Children) != 1 || g.Children[0].Path != "Gifts/Books" || g.Children[0].Wishes != 3 {
+		t.Errorf("children = %+v", g.Children)
+	}
+	if !reflect.DeepEqual(stats[0].Unconverted, []string{"CHF"}) || stats[0].BaseValue.Count != 0 {
+		t.Errorf("games = %+v", stats[0])
+	}
+}
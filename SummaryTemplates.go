@@ -0,0 +1,296 @@
+package koiApi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"gopkg.in/yaml.v3"
+)
+
+// Summarizer renders one-line summaries from user-defined text/template strings, one per type, such as
+//
+//	item: '{{.Name}} by {{datum "Author"}} in {{collectionPath}} ({{ago .CreatedAt}})'
+//
+// Templates are executed with the object as dot. Besides the text/template builtins they can call:
+//
+//	datum "Label"   value of the object's datum with that label, or ""
+//	tags            labels of the object's tags; prints comma separated and can be ranged over
+//	collectionPath  path of the object's collection, or of a collection itself, e.g. Books/Science fiction
+//	ago .CreatedAt  relative time such as "3 days ago"; accepts time.Time and Nullable[time.Time]
+//	money v [cur]   price v formatted in currency cur, defaulting to Summarizer.Currency
+//	join list sep   strings.Join
+//
+// Relations are fetched only when a template calls the helper that needs them, and are cached for the life of
+// the Summarizer.
+type Summarizer struct {
+	Currency  string     // Default currency for money; defaults to the machine's locale
+	Formatter *Formatter // Formats money in a user's locale when set
+
+	client      Client
+	templates   map[string]*template.Template // Keyed by lower-case type name
+	mu          sync.Mutex                    // Guards the caches below, but is never held during a fetch
+	data        map[string][]*Datum           // By object IRI
+	tags        map[string]TagLabels          // By object IRI
+	collections map[string]*Collection        // By IRI
+}
+
+// TagLabels is the result of the tags helper.
+type TagLabels []string
+
+func (t TagLabels) String() string {
+	return strings.Join(t, ", ")
+}
+
+// NewSummarizer parses templates, keyed by type name (item, Collection, ...), to be rendered through c, which
+// may be the live client or a CachedClient. A nil c means GetClient().
+func NewSummarizer(c Client, templates map[string]string) (*Summarizer, error) {
+	if c == nil {
+		c = GetClient()
+	}
+	s := &Summarizer{
+		Currency:    getDefaultCurrency(),
+		client:      c,
+		templates:   map[string]*template.Template{},
+		data:        map[string][]*Datum{},
+		tags:        map[string]TagLabels{},
+		collections: map[string]*Collection{},
+	}
+	for name, text := range templates {
+		key := strings.ToLower(name)
+		tmpl, err := template.New(key).Funcs(s.funcs(nil)).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("parsing summary template for %s: %w", name, err)
+		}
+		s.templates[key] = tmpl
+	}
+	return s, nil
+}
+
+// LoadSummaryTemplates reads templates keyed by type name from a YAML or JSON file.
+func LoadSummaryTemplates(filename string) (map[string]string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading summary templates: %w", err)
+	}
+	templates := map[string]string{}
+	if strings.EqualFold(filepath.Ext(filename), ".json") {
+		err = json.Unmarshal(data, &templates)
+	} else {
+		err = yaml.Unmarshal(data, &templates)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decoding summary templates %s: %w", filename, err)
+	}
+	return templates, nil
+}
+
+// Has reports whether there is a template for obj's type.
+func (s *Summarizer) Has(obj KoiObject) bool {
+	_, ok := s.templates[summaryKey(obj)]
+	return ok
+}
+
+// Summary renders obj with the template for its type, or with its own Summary method if there is none.
+func (s *Summarizer) Summary(obj KoiObject) (string, error) {
+	tmpl, ok := s.templates[summaryKey(obj)]
+	if !ok {
+		switch o := obj.(type) {
+		case interface{ Summary(...int) string }:
+			return o.Summary(), nil
+		case interface{ Summary() string }:
+			return o.Summary(), nil
+		}
+		return fmt.Sprintf("%T %s", obj, obj.GetID()), nil
+	}
+	// Helpers are bound to obj on a clone so that concurrent calls do not share state.
+	bound, err := tmpl.Clone()
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := bound.Funcs(s.funcs(obj)).Execute(&buf, obj); err != nil {
+		return "", fmt.Errorf("rendering summary of %s: %w", obj.IRI(), err)
+	}
+	return buf.String(), nil
+}
+
+func summaryKey(obj KoiObject) string {
+	return strings.ToLower(reflect.TypeOf(obj).Elem().Name())
+}
+
+// funcs returns the helpers bound to obj; a nil obj gives placeholders for parsing.
+func (s *Summarizer) funcs(obj KoiObject) template.FuncMap {
+	return template.FuncMap{
+		"datum":          func(label string) (string, error) { return s.datum(obj, label) },
+		"tags":           func() (TagLabels, error) { return s.tagLabels(obj) },
+		"collectionPath": func() (string, error) { return s.collectionPath(obj) },
+		"ago":            func(t any) (string, error) { return ago(t, time.Now()) },
+		"money":          func(v any, cur ...string) (string, error) { return s.money(v, cur...) },
+		"join":           func(list []string, sep string) string { return strings.Join(list, sep) },
+	}
+}
+
+func (s *Summarizer) datum(obj KoiObject, label string) (string, error) {
+	s.mu.Lock()
+	data, ok := s.data[obj.IRI()]
+	s.mu.Unlock()
+	if !ok {
+		if err := s.client.listResources(obj.IRI()+"/data", &data); err != nil {
+			return "", fmt.Errorf("listing data of %s: %w", obj.IRI(), err)
+		}
+		s.mu.Lock()
+		s.data[obj.IRI()] = data
+		s.mu.Unlock()
+	}
+	for _, d := range data {
+		if strings.EqualFold(d.Label, label) {
+			return d.Value, nil
+		}
+	}
+	return "", nil
+}
+
+func (s *Summarizer) tagLabels(obj KoiObject) (TagLabels, error) {
+	s.mu.Lock()
+	labels, ok := s.tags[obj.IRI()]
+	s.mu.Unlock()
+	if ok {
+		return labels, nil
+	}
+	var tags []*Tag
+	if err := s.client.listResources(obj.IRI()+"/tags", &tags); err != nil {
+		return nil, fmt.Errorf("listing tags of %s: %w", obj.IRI(), err)
+	}
+	labels = make(TagLabels, len(tags))
+	for i, t := range tags {
+		labels[i] = t.Label
+	}
+	s.mu.Lock()
+	s.tags[obj.IRI()] = labels
+	s.mu.Unlock()
+	return labels, nil
+}
+
+func (s *Summarizer) collectionPath(obj KoiObject) (string, error) {
+	iri := ""
+	switch o := obj.(type) {
+	case *Collection:
+		iri = o.IRI()
+	case *Item:
+		iri = o.Collection
+	case *Datum:
+		iri = o.Collection
+	}
+	var titles []string
+	seen := map[string]bool{}
+	for iri != "" && !seen[iri] {
+		seen[iri] = true
+		s.mu.Lock()
+		col, ok := s.collections[iri]
+		s.mu.Unlock()
+		if !ok {
+			col = &Collection{}
+			if err := s.client.getResource(iri, col); err != nil {
+				return "", fmt.Errorf("fetching %s: %w", iri, err)
+			}
+			s.mu.Lock()
+			s.collections[iri] = col
+			s.mu.Unlock()
+		}
+		titles = append([]string{col.Title}, titles...)
+		iri = col.Parent
+	}
+	return strings.Join(titles, "/"), nil
+}
+
+func (s *Summarizer) money(v any, cur ...string) (string, error) {
+	code := getArg(s.Currency, cur)
+	amount, err := toFloat(v)
+	if err != nil || code == "" {
+		return fmt.Sprint(v), err
+	}
+	unit, err := currency.ParseISO(code)
+	if err != nil {
+		return "", fmt.Errorf("currency %q: %w", code, err)
+	}
+	if s.Formatter != nil {
+		return s.Formatter.Price(amount, unit.String())
+	}
+	return message.NewPrinter(language.English).Sprint(currency.Symbol(unit.Amount(amount))), nil
+}
+
+func toFloat(v any) (float64, error) {
+	switch x := v.(type) {
+	case float64:
+		return x, nil
+	case float32:
+		return float64(x), nil
+	case int:
+		return float64(x), nil
+	case string:
+		if x == "" {
+			return 0, nil
+		}
+		return strconv.ParseFloat(strings.TrimSpace(x), 64)
+	}
+	return 0, fmt.Errorf("cannot format %T as money", v)
+}
+
+// ago describes t relative to now, e.g. "3 days ago" or "in 2 hours".
+func ago(t any, now time.Time) (string, error) {
+	var at time.Time
+	switch x := t.(type) {
+	case time.Time:
+		at = x
+	case Nullable[time.Time]:
+		at = x.OrZero()
+	case *time.Time:
+		if x != nil {
+			at = *x
+		}
+	default:
+		return "", fmt.Errorf("ago needs a time, got %T", t)
+	}
+	if at.IsZero() {
+		return "never", nil
+	}
+	d := now.Sub(at)
+	future := d < 0
+	d = time.Duration(math.Abs(float64(d)))
+	var n int
+	var unit string
+	switch {
+	case d < time.Minute:
+		return "just now", nil
+	case d < time.Hour:
+		n, unit = int(d.Minutes()), "minute"
+	case d < 24*time.Hour:
+		n, unit = int(d.Hours()), "hour"
+	case d < 30*24*time.Hour:
+		n, unit = int(d.Hours()/24), "day"
+	case d < 365*24*time.Hour:
+		n, unit = int(d.Hours()/24/30), "month"
+	default:
+		n, unit = int(d.Hours()/24/365), "year"
+	}
+	if n != 1 {
+		unit += "s"
+	}
+	if future {
+		return fmt.Sprintf("in %d %s", n, unit), nil
+	}
+	return fmt.Sprintf("%d %s ago", n, unit), nil
+}
@@ -0,0 +1,463 @@
+package koiApi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"time"
+)
+
+// ConflictPolicy decides what happens when an object changed on both servers since the last sync.
+type ConflictPolicy string
+
+const (
+	ConflictSourceWins      ConflictPolicy = "source"      // Overwrite the destination (default)
+	ConflictDestinationWins ConflictPolicy = "destination" // Keep the destination copy
+	ConflictNewest          ConflictPolicy = "newest"      // Keep whichever side was updated last
+	ConflictSkip            ConflictPolicy = "skip"        // Leave both sides alone and report the conflict
+)
+
+// SyncOptions configures a Syncer.
+type SyncOptions struct {
+	Collections      []string       // Source collection IRIs to mirror with their descendants; empty mirrors all root collections
+	Visibilities     []Visibility   // Only mirror objects whose FinalVisibility is listed; empty mirrors everything
+	PropagateDeletes bool           // Delete destination objects whose source is gone or no longer selected
+	CopyMedia        bool           // Copy images, files and videos
+	Conflict         ConflictPolicy // Conflict policy; defaults to ConflictSourceWins
+	StateFile        string         // Path of the JSON file holding the ID mapping between runs
+}
+
+// SyncMapping links a source object to its copy on the destination.
+type SyncMapping struct {
+	Kind                 string    `json:"kind"`                 // collection, item, datum, tag or choicelist
+	Destination          string    `json:"destination"`          // Destination IRI
+	SourceUpdatedAt      time.Time `json:"sourceUpdatedAt"`      // Source timestamp at the last sync
+	DestinationUpdatedAt time.Time `json:"destinationUpdatedAt"` // Destination timestamp at the last sync
+}
+
+// SyncState is persisted to SyncOptions.StateFile between runs.
+type SyncState struct {
+	LastSync time.Time               `json:"lastSync"`
+	Mappings map[string]*SyncMapping `json:"mappings"` // Keyed by source IRI
+}
+
+// SyncConflict describes an object that changed on both sides.
+type SyncConflict struct {
+	Source      string
+	Destination string
+	Resolution  string
+}
+
+// SyncReport summarises a sync run.
+type SyncReport struct {
+	Created   []string // Source IRIs created on the destination
+	Updated   []string // Source IRIs updated on the destination
+	Deleted   []string // Destination IRIs deleted
+	Conflicts []SyncConflict
+}
+
+// Summary
+func (r *SyncReport) Summary() string {
+	return fmt.Sprintf("created %d, updated %d, deleted %d, conflicts %d", len(r.Created), len(r.Updated), len(r.Deleted), len(r.Conflicts))
+}
+
+// Syncer mirrors selected collections, with their items and data, from one server to another. Creates on the
+// destination fail with ErrFeatureDisabled, like Create, when the destination user has the feature turned off.
+type Syncer struct {
+	src, dst *koiClient
+	opts     SyncOptions
+	state    *SyncState
+	seen     map[string]bool
+	report   *SyncReport
+	tags     map[string]string // destination tag IRI by label
+	choices  map[string]string // destination choice list IRI by name
+}
+
+// NewSyncer creates a Syncer between two logged-in clients, loading the mapping from opts.StateFile if it exists.
+func NewSyncer(src, dst *koiClient, opts SyncOptions) (*Syncer, error) {
+	if opts.Conflict == "" {
+		opts.Conflict = ConflictSourceWins
+	}
+	s := &Syncer{src: src, dst: dst, opts: opts, state: &SyncState{Mappings: map[string]*SyncMapping{}}}
+	if opts.StateFile == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(opts.StateFile)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading sync state %s: %w", opts.StateFile, err)
+	}
+	if err := json.Unmarshal(data, s.state); err != nil {
+		return nil, fmt.Errorf("decoding sync state %s: %w", opts.StateFile, err)
+	}
+	if s.state.Mappings == nil {
+		s.state.Mappings = map[string]*SyncMapping{}
+	}
+	return s, nil
+}
+
+// State returns the current mapping state.
+func (s *Syncer) State() *SyncState {
+	return s.state
+}
+
+// Run performs one sync pass. The state file is written even when the pass fails part way, so objects
+// already copied are not duplicated on the next run.
+func (s *Syncer) Run() (report *SyncReport, err error) {
+	s.seen = map[string]bool{}
+	s.report = &SyncReport{}
+	s.tags = nil
+	s.choices = nil
+	started := time.Now()
+	defer func() {
+		if err == nil {
+			s.state.LastSync = started
+		}
+		if saveErr := s.saveState(); saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}()
+
+	var all []*Collection
+	if err := s.src.listResources(baseObjPath(&Collection{}), &all); err != nil {
+		return s.report, fmt.Errorf("listing source collections: %w", err)
+	}
+	collections := s.selectCollections(all)
+
+	for _, col := range collections {
+		if !s.visible(col.FinalVisibility) {
+			continue
+		}
+		parent := ""
+		if col.Parent != "" {
+			m, ok := s.state.Mappings[col.Parent]
+			if !ok || !s.seen[col.Parent] {
+				continue // parent was filtered out, so the subtree is not mirrored
+			}
+			parent = m.Destination
+		}
+		dstIRI, wrote, err := syncObject(s, "collection", col, func(c *Collection) error {
+			c.Parent = parent
+			c.ItemsDefaultTemplate = "" // templates are not mirrored
+			return nil
+		})
+		if err != nil {
+			return s.report, err
+		}
+		if wrote && s.opts.CopyMedia && col.Image != "" {
+			if err := copyMedia(context.Background(), s.src, s.dst, col.Image, dstIRI+"/image", "fileImage", ""); err != nil {
+				return s.report, err
+			}
+		}
+		if err := s.syncData(col.IRI()+"/data", dstIRI, "collection"); err != nil {
+			return s.report, err
+		}
+		if err := s.syncItems(col, dstIRI); err != nil {
+			return s.report, err
+		}
+	}
+
+	if s.opts.PropagateDeletes {
+		if err := s.propagateDeletes(); err != nil {
+			return s.report, err
+		}
+	}
+	return s.report, nil
+}
+
+// selectCollections returns the selected collections and their descendants, parents before children.
+func (s *Syncer) selectCollections(all []*Collection) []*Collection {
+	children := make(map[string][]*Collection)
+	var roots []*Collection
+	for _, c := range all {
+		children[c.Parent] = append(children[c.Parent], c)
+		if (len(s.opts.Collections) == 0 && c.Parent == "") || slices.Contains(s.opts.Collections, c.IRI()) {
+			roots = append(roots, c)
+		}
+	}
+	var out []*Collection
+	queue := roots
+	added := make(map[string]bool)
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		if added[c.IRI()] {
+			continue
+		}
+		added[c.IRI()] = true
+		out = append(out, c)
+		queue = append(queue, children[c.IRI()]...)
+	}
+	// A selected root's parent is not mirrored, so the copy becomes a root on the destination.
+	for _, c := range roots {
+		if !added[c.Parent] {
+			c.Parent = ""
+		}
+	}
+	return out
+}
+
+func (s *Syncer) syncItems(col *Collection, dstCollection string) error {
+	var items []*Item
+	if err := s.src.listResources(col.IRI()+"/items", &items); err != nil {
+		return fmt.Errorf("listing items of %s: %w", col.IRI(), err)
+	}
+	for _, item := range items {
+		if !s.visible(item.FinalVisibility) {
+			continue
+		}
+		dstIRI, wrote, err := syncObject(s, "item", item, func(i *Item) error {
+			var tags []*Tag
+			if err := s.src.listResources(item.IRI()+"/tags", &tags); err != nil {
+				return fmt.Errorf("listing tags: %w", err)
+			}
+			i.Collection = dstCollection
+			i.RelatedItems = nil
+			i.Tags = nil
+			for _, t := range tags {
+				iri, err := s.mirrorTag(t)
+				if err != nil {
+					return err
+				}
+				i.Tags = append(i.Tags, iri)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if wrote && s.opts.CopyMedia && item.Image != "" {
+			if err := copyMedia(context.Background(), s.src, s.dst, item.Image, dstIRI+"/image", "fileImage", ""); err != nil {
+				return err
+			}
+		}
+		if err := s.syncData(item.IRI()+"/data", dstIRI, "item"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncData mirrors the data listed at path onto the destination item or collection.
+func (s *Syncer) syncData(path, dstOwner, ownerKind string) error {
+	var data []*Datum
+	if err := s.src.listResources(path, &data); err != nil {
+		return fmt.Errorf("listing %s: %w", path, err)
+	}
+	for _, d := range data {
+		if !s.visible(d.FinalVisibility) {
+			continue
+		}
+		dstIRI, wrote, err := syncObject(s, "datum", d, func(nd *Datum) error {
+			nd.Item, nd.Collection = "", ""
+			if ownerKind == "item" {
+				nd.Item = dstOwner
+			} else {
+				nd.Collection = dstOwner
+			}
+			if d.ChoiceList != "" {
+				iri, err := s.mirrorChoiceList(d.ChoiceList)
+				if err != nil {
+					return err
+				}
+				nd.ChoiceList = iri
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if !wrote || !s.opts.CopyMedia {
+			continue
+		}
+		if err := copyDatumMedia(context.Background(), s.src, s.dst, d, dstIRI); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncObject creates or updates the destination copy of obj, returning its IRI and whether it was written.
+// prepare adjusts the writable copy, e.g. to translate IRIs into destination IRIs.
+func syncObject[T KoiObject](s *Syncer, kind string, obj T, prepare func(T) error) (string, bool, error) {
+	srcIRI := obj.IRI()
+	s.seen[srcIRI] = true
+	srcUpdated := modifiedAt(obj)
+
+	out := writableCopy(obj)
+	mapping, ok := s.state.Mappings[srcIRI]
+	if ok && !srcUpdated.After(mapping.SourceUpdatedAt) {
+		return mapping.Destination, false, nil
+	}
+	if err := prepare(out); err != nil {
+		return "", false, fmt.Errorf("preparing %s: %w", srcIRI, err)
+	}
+
+	if ok {
+		var current T
+		err := s.dst.getResource(mapping.Destination, &current)
+		switch {
+		case errors.Is(err, ErrNotFound):
+			ok = false // removed on the destination; recreate it
+		case err != nil:
+			return "", false, fmt.Errorf("fetching %s: %w", mapping.Destination, err)
+		case modifiedAt(current).After(mapping.DestinationUpdatedAt):
+			if !s.resolveConflict(srcIRI, mapping, srcUpdated, modifiedAt(current)) {
+				return mapping.Destination, false, nil
+			}
+		}
+	}
+
+	var written T
+	if ok {
+		if err := s.dst.patchResource(mapping.Destination, out, &written); err != nil {
+			return "", false, fmt.Errorf("updating %s: %w", mapping.Destination, err)
+		}
+		s.report.Updated = append(s.report.Updated, srcIRI)
+	} else {
+		if err := s.dst.createResource(context.Background(), baseObjPath(obj), obj, out, &written); err != nil {
+			return "", false, fmt.Errorf("creating copy of %s: %w", srcIRI, err)
+		}
+		s.report.Created = append(s.report.Created, srcIRI)
+	}
+	s.state.Mappings[srcIRI] = &SyncMapping{
+		Kind:                 kind,
+		Destination:          written.IRI(),
+		SourceUpdatedAt:      srcUpdated,
+		DestinationUpdatedAt: modifiedAt(written),
+	}
+	return written.IRI(), true, nil
+}
+
+// resolveConflict applies the conflict policy and reports whether the destination should be overwritten.
+func (s *Syncer) resolveConflict(srcIRI string, mapping *SyncMapping, srcUpdated, dstUpdated time.Time) bool {
+	overwrite := false
+	switch s.opts.Conflict {
+	case ConflictSourceWins:
+		overwrite = true
+	case ConflictNewest:
+		overwrite = srcUpdated.After(dstUpdated)
+	case ConflictDestinationWins:
+		// Accept the destination as the new baseline so the conflict is not reported again.
+		mapping.SourceUpdatedAt = srcUpdated
+		mapping.DestinationUpdatedAt = dstUpdated
+	}
+	resolution := "kept destination"
+	if overwrite {
+		resolution = "overwrote destination"
+	} else if s.opts.Conflict == ConflictSkip {
+		resolution = "skipped"
+	}
+	s.report.Conflicts = append(s.report.Conflicts, SyncConflict{Source: srcIRI, Destination: mapping.Destination, Resolution: resolution})
+	return overwrite
+}
+
+// propagateDeletes removes destination copies of objects not seen in this run; data first, collections last.
+func (s *Syncer) propagateDeletes() error {
+	order := map[string]int{"datum": 0, "item": 1, "collection": 2}
+	var gone []string
+	for srcIRI, m := range s.state.Mappings {
+		if _, ok := order[m.Kind]; ok && !s.seen[srcIRI] {
+			gone = append(gone, srcIRI)
+		}
+	}
+	sort.Slice(gone, func(i, j int) bool {
+		return order[s.state.Mappings[gone[i]].Kind] < order[s.state.Mappings[gone[j]].Kind]
+	})
+	for _, srcIRI := range gone {
+		dstIRI := s.state.Mappings[srcIRI].Destination
+		if err := s.dst.deleteResource(dstIRI); err != nil && !errors.Is(err, ErrNotFound) {
+			return fmt.Errorf("deleting %s: %w", dstIRI, err)
+		}
+		delete(s.state.Mappings, srcIRI)
+		s.report.Deleted = append(s.report.Deleted, dstIRI)
+	}
+	return nil
+}
+
+// mirrorTag returns the destination tag with the same label, creating it if needed.
+func (s *Syncer) mirrorTag(t *Tag) (string, error) {
+	if s.tags == nil {
+		var tags []*Tag
+		if err := s.dst.listResources(baseObjPath(&Tag{}), &tags); err != nil {
+			return "", fmt.Errorf("listing destination tags: %w", err)
+		}
+		s.tags = make(map[string]string, len(tags))
+		for _, dt := range tags {
+			s.tags[dt.Label] = dt.IRI()
+		}
+	}
+	s.seen[t.IRI()] = true
+	if iri, ok := s.tags[t.Label]; ok {
+		return iri, nil
+	}
+	out := writableCopy(t)
+	out.Category = "" // tag categories are not mirrored
+	var created *Tag
+	if err := s.dst.createResource(context.Background(), baseObjPath(t), t, out, &created); err != nil {
+		return "", fmt.Errorf("creating tag %s: %w", t.Label, err)
+	}
+	s.tags[t.Label] = created.IRI()
+	s.state.Mappings[t.IRI()] = &SyncMapping{Kind: "tag", Destination: created.IRI(), SourceUpdatedAt: modifiedAt(t), DestinationUpdatedAt: modifiedAt(created)}
+	s.report.Created = append(s.report.Created, t.IRI())
+	return created.IRI(), nil
+}
+
+// mirrorChoiceList returns the destination choice list with the same name as the source one, creating it if needed.
+func (s *Syncer) mirrorChoiceList(srcIRI string) (string, error) {
+	if s.choices == nil {
+		var lists []*ChoiceList
+		if err := s.dst.listResources(baseObjPath(&ChoiceList{}), &lists); err != nil {
+			return "", fmt.Errorf("listing destination choice lists: %w", err)
+		}
+		s.choices = make(map[string]string, len(lists))
+		for _, cl := range lists {
+			s.choices[cl.Name] = cl.IRI()
+		}
+	}
+	if m, ok := s.state.Mappings[srcIRI]; ok && s.seen[srcIRI] {
+		return m.Destination, nil
+	}
+	var cl *ChoiceList
+	if err := s.src.getResource(srcIRI, &cl); err != nil {
+		return "", fmt.Errorf("fetching %s: %w", srcIRI, err)
+	}
+	s.seen[srcIRI] = true
+	if iri, ok := s.choices[cl.Name]; ok {
+		s.state.Mappings[srcIRI] = &SyncMapping{Kind: "choicelist", Destination: iri, SourceUpdatedAt: modifiedAt(cl)}
+		return iri, nil
+	}
+	var created *ChoiceList
+	if err := s.dst.createResource(context.Background(), baseObjPath(cl), cl, writableCopy(cl), &created); err != nil {
+		return "", fmt.Errorf("creating choice list %s: %w", cl.Name, err)
+	}
+	s.choices[cl.Name] = created.IRI()
+	s.state.Mappings[srcIRI] = &SyncMapping{Kind: "choicelist", Destination: created.IRI(), SourceUpdatedAt: modifiedAt(cl), DestinationUpdatedAt: modifiedAt(created)}
+	s.report.Created = append(s.report.Created, srcIRI)
+	return created.IRI(), nil
+}
+
+func (s *Syncer) visible(v Visibility) bool {
+	return len(s.opts.Visibilities) == 0 || slices.Contains(s.opts.Visibilities, v)
+}
+
+func (s *Syncer) saveState() error {
+	if s.opts.StateFile == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding sync state: %w", err)
+	}
+	tmp := s.opts.StateFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("writing sync state: %w", err)
+	}
+	return os.Rename(tmp, s.opts.StateFile)
+}
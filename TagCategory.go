@@ -7,16 +7,16 @@ import (
 
 // TagCategory represents a tag category in Koillection, combining fields for JSON-LD and API interactions.
 type TagCategory struct {
-	Context     Context   `json:"@context,omitempty" access:"rw"`    // JSON-LD only
-	_ID         ID        `json:"@id,omitempty" access:"ro"`         // JSON-LD only
-	Type        string    `json:"@type,omitempty" access:"rw"`       // JSON-LD only
-	ID          ID        `json:"id,omitempty" access:"ro"`          // Identifier
-	Label       string    `json:"label" access:"rw"`                 // Category label
-	Description string    `json:"description,omitempty" access:"rw"` // Category description
-	Color       string    `json:"color" access:"rw"`                 // Color code
-	Owner       string    `json:"owner,omitempty" access:"ro"`       // Owner IRI
-	CreatedAt   time.Time `json:"createdAt" access:"ro"`             // Creation timestamp
-	UpdatedAt   time.Time `json:"updatedAt,omitempty" access:"ro"`   // Update timestamp
+	Context     Context             `json:"@context,omitempty" access:"rw"`            // JSON-LD only
+	_ID         ID                  `json:"@id,omitempty" access:"ro"`                 // JSON-LD only
+	Type        string              `json:"@type,omitempty" access:"rw"`               // JSON-LD only
+	ID          ID                  `json:"id,omitempty" access:"ro" render:"default"` // Identifier
+	Label       string              `json:"label" access:"rw" render:"default"`        // Category label
+	Description string              `json:"description,omitempty" access:"rw"`         // Category description
+	Color       string              `json:"color" access:"rw" render:"default"`        // Color code
+	Owner       string              `json:"owner,omitempty" access:"ro"`               // Owner IRI
+	CreatedAt   time.Time           `json:"createdAt,omitzero" access:"ro"`            // Creation timestamp
+	UpdatedAt   Nullable[time.Time] `json:"updatedAt,omitzero" access:"ro"`            // Update timestamp
 
 }
 
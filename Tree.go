@@ -0,0 +1,309 @@
+package koiApi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// TreeObject is a KoiObject nested through a Parent IRI and a /children endpoint.
+type TreeObject interface {
+	*Collection | *Album | *Wishlist
+	KoiObject
+}
+
+// TreeNode is an object in a hierarchy with its fetched children.
+type TreeNode[T TreeObject] struct {
+	Object   T
+	Parent   *TreeNode[T] // nil for the root of the fetched tree
+	Children []*TreeNode[T]
+	Depth    int // 0 for the root of the fetched tree
+}
+
+// SkipChildren can be returned by a pre-order Walk visitor to skip the node's children.
+var SkipChildren = errors.New("skip children")
+
+// treeFetchers bounds the concurrent requests made while fetching a tree.
+const treeFetchers = 8
+
+// Tree fetches root and all of its descendants, requesting sibling subtrees concurrently.
+func Tree[T TreeObject](ctx context.Context, root T) (*TreeNode[T], error) {
+	node := &TreeNode[T]{Object: root}
+	f := &treeFetch{ctx: ctx, sem: make(chan struct{}, treeFetchers)}
+	f.wg.Add(1)
+	go fetchChildren(f, node, map[string]bool{root.IRI(): true})
+	f.wg.Wait()
+	return node, f.err
+}
+
+// Forest builds every tree of obj's type from a single listing, without per-node requests.
+func Forest[T TreeObject](ctx context.Context, obj T) ([]*TreeNode[T], error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	all, err := List(obj)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make(map[string]*TreeNode[T], len(all))
+	for _, o := range all {
+		nodes[o.IRI()] = &TreeNode[T]{Object: o}
+	}
+	var roots []*TreeNode[T]
+	for _, o := range all {
+		n := nodes[o.IRI()]
+		if p, ok := nodes[parentOf(o)]; ok {
+			n.Parent = p
+			p.Children = append(p.Children, n)
+		} else {
+			roots = append(roots, n)
+		}
+	}
+	for _, r := range roots {
+		r.Walk(func(n *TreeNode[T]) error {
+			if n.Parent != nil {
+				n.Depth = n.Parent.Depth + 1
+			}
+			return nil
+		}, nil)
+	}
+	return roots, nil
+}
+
+type treeFetch struct {
+	ctx context.Context
+	sem chan struct{}
+	wg  sync.WaitGroup
+	mu  sync.Mutex
+	err error
+}
+
+func (f *treeFetch) fail(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err == nil {
+		f.err = err
+	}
+}
+
+func fetchChildren[T TreeObject](f *treeFetch, node *TreeNode[T], ancestors map[string]bool) {
+	defer f.wg.Done()
+	if err := f.ctx.Err(); err != nil {
+		f.fail(err)
+		return
+	}
+	f.sem <- struct{}{}
+	children, err := ListChildren(node.Object)
+	<-f.sem
+	if err != nil {
+		f.fail(fmt.Errorf("listing children of %s: %w", node.Object.IRI(), err))
+		return
+	}
+	for _, child := range children {
+		if ancestors[child.IRI()] {
+			f.fail(fmt.Errorf("%s is its own ancestor", child.IRI()))
+			return
+		}
+		node.Children = append(node.Children, &TreeNode[T]{Object: child, Parent: node, Depth: node.Depth + 1})
+	}
+	for _, n := range node.Children {
+		path := make(map[string]bool, len(ancestors)+1)
+		for k := range ancestors {
+			path[k] = true
+		}
+		path[n.Object.IRI()] = true
+		f.wg.Add(1)
+		go fetchChildren(f, n, path)
+	}
+}
+
+// Walk visits n and its descendants depth first, calling pre before a node's children and post after them.
+// Either visitor may be nil. A pre visitor returning SkipChildren skips the children; any other error stops
+// the walk and is returned.
+func (n *TreeNode[T]) Walk(pre, post func(*TreeNode[T]) error) error {
+	if pre != nil {
+		if err := pre(n); err == SkipChildren {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+	for _, child := range n.Children {
+		if err := child.Walk(pre, post); err != nil {
+			return err
+		}
+	}
+	if post != nil {
+		return post(n)
+	}
+	return nil
+}
+
+// Find returns the node holding the object with the given IRI.
+func (n *TreeNode[T]) Find(iri string) (*TreeNode[T], bool) {
+	var found *TreeNode[T]
+	n.Walk(func(m *TreeNode[T]) error {
+		if m.Object.IRI() == iri {
+			found = m
+			return errors.New("found")
+		}
+		return nil
+	}, nil)
+	return found, found != nil
+}
+
+// Descendants returns every node below n in pre-order.
+func (n *TreeNode[T]) Descendants() []*TreeNode[T] {
+	var out []*TreeNode[T]
+	n.Walk(func(m *TreeNode[T]) error {
+		if m != n {
+			out = append(out, m)
+		}
+		return nil
+	}, nil)
+	return out
+}
+
+// Text renders the tree with one indented title per node.
+func (n *TreeNode[T]) Text() string {
+	var s string
+	n.Walk(func(m *TreeNode[T]) error {
+		s += fmt.Sprintf("%s%s\n", indentChars(m.Depth*4), nodeTitle(m.Object))
+		return nil
+	}, nil)
+	return s
+}
+
+// Contents lists what n and its descendants hold: items for collections, photos for albums and wishes for
+// wishlists. Nodes are queried concurrently; the result follows the tree's pre-order.
+func Contents[T TreeObject](ctx context.Context, n *TreeNode[T]) ([]KoiObject, error) {
+	nodes := append([]*TreeNode[T]{n}, n.Descendants()...)
+	results := make([][]KoiObject, len(nodes))
+	f := &treeFetch{ctx: ctx, sem: make(chan struct{}, treeFetchers)}
+	for i, node := range nodes {
+		f.wg.Add(1)
+		go func() {
+			defer f.wg.Done()
+			if err := ctx.Err(); err != nil {
+				f.fail(err)
+				return
+			}
+			f.sem <- struct{}{}
+			defer func() { <-f.sem }()
+			objs, err := nodeContents(node.Object)
+			if err != nil {
+				f.fail(fmt.Errorf("listing contents of %s: %w", node.Object.IRI(), err))
+			}
+			results[i] = objs
+		}()
+	}
+	f.wg.Wait()
+	var out []KoiObject
+	for _, r := range results {
+		out = append(out, r...)
+	}
+	return out, f.err
+}
+
+// DescendantItems returns the items in a collection tree.
+func DescendantItems(ctx context.Context, n *TreeNode[*Collection]) ([]*Item, error) {
+	objs, err := Contents(ctx, n)
+	items := make([]*Item, 0, len(objs))
+	for _, o := range objs {
+		items = append(items, o.(*Item))
+	}
+	return items, err
+}
+
+func nodeContents(obj KoiObject) ([]KoiObject, error) {
+	var out []KoiObject
+	switch o := obj.(type) {
+	case *Collection:
+		items, err := ListItems(o)
+		for _, i := range items {
+			out = append(out, i)
+		}
+		return out, err
+	case *Album:
+		photos, err := ListPhotos(o)
+		for _, p := range photos {
+			out = append(out, p)
+		}
+		return out, err
+	case *Wishlist:
+		wishes, err := ListWishes(o)
+		for _, w := range wishes {
+			out = append(out, w)
+		}
+		return out, err
+	}
+	return nil, fmt.Errorf("%T has no contents", obj)
+}
+
+// Path returns the breadcrumb from the root of obj's hierarchy down to obj itself.
+func Path[T TreeObject](ctx context.Context, obj T) ([]T, error) {
+	path := []T{obj}
+	seen := map[string]bool{obj.IRI(): true}
+	c := GetClient()
+	for parent := parentOf(obj); parent != ""; parent = parentOf(path[0]) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if seen[parent] {
+			return nil, fmt.Errorf("%s is its own ancestor", parent)
+		}
+		seen[parent] = true
+		p := reflect.New(reflect.TypeOf(obj).Elem()).Interface().(T)
+		if err := c.getResource(parent, p); err != nil {
+			return nil, fmt.Errorf("fetching %s: %w", parent, err)
+		}
+		path = append([]T{p}, path...)
+	}
+	return path, nil
+}
+
+// Move makes newParent the parent of obj, or makes obj a root when newParent is nil. It refuses to move an
+// object below itself.
+func Move[T TreeObject](ctx context.Context, obj, newParent T) (T, error) {
+	var parentIRI any // nil clears the parent
+	if reflect.ValueOf(newParent).IsNil() {
+		parentIRI = nil
+	} else {
+		ancestors, err := Path(ctx, newParent)
+		if err != nil {
+			return obj, err
+		}
+		for _, a := range ancestors {
+			if a.IRI() == obj.IRI() {
+				return obj, fmt.Errorf("cannot move %s below %s: %w", obj.IRI(), newParent.IRI(), ErrInvalidInput)
+			}
+		}
+		parentIRI = newParent.IRI()
+	}
+	moved := reflect.New(reflect.TypeOf(obj).Elem()).Interface().(T)
+	if err := GetClient().patchResource(obj.IRI(), map[string]any{"parent": parentIRI}, moved); err != nil {
+		return obj, err
+	}
+	return moved, nil
+}
+
+func parentOf(obj KoiObject) string {
+	if f := reflect.ValueOf(obj).Elem().FieldByName("Parent"); f.IsValid() && f.Kind() == reflect.String {
+		return f.String()
+	}
+	return ""
+}
+
+func nodeTitle(obj KoiObject) string {
+	switch o := obj.(type) {
+	case *Collection:
+		return o.Title
+	case *Album:
+		return o.Title
+	case *Wishlist:
+		return o.Name
+	}
+	return obj.IRI()
+}
@@ -0,0 +1,278 @@
+package koiApi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// TxOp is the kind of change a Tx recorded.
+type TxOp string
+
+const (
+	TxCreated TxOp = "create" // Undone by deleting the object
+	TxPatched TxOp = "patch"  // Undone by patching the snapshot back
+)
+
+// TxEntry is one recorded change.
+type TxEntry struct {
+	Op       TxOp            `json:"op"`
+	Kind     string          `json:"kind"`               // basePathForType key, e.g. item
+	IRI      string          `json:"iri"`                // Object the change applied to
+	Snapshot json.RawMessage `json:"snapshot,omitempty"` // Patched fields before a patch, keyed by JSON name
+}
+
+// Tx is a compensating transaction: it records the creates and patches made through it so that they can be
+// undone in reverse order if a later step fails. The server has no transactions, so a rollback is a new
+// series of requests and can itself fail part way; the journal file lets ResumeRollback finish it later.
+type Tx struct {
+	mu      sync.Mutex
+	journal string // Empty keeps the log in memory only
+	entries []*TxEntry
+	closed  bool
+}
+
+// BeginTx starts a transaction journaled to the given file; pass "" for no journal.
+func BeginTx(journal string) (*Tx, error) {
+	tx := &Tx{journal: journal}
+	if journal != "" {
+		if _, err := os.Stat(journal); err == nil {
+			return nil, fmt.Errorf("journal %s exists; resume or remove it first", journal)
+		}
+		if err := tx.save(); err != nil {
+			return nil, err
+		}
+	}
+	return tx, nil
+}
+
+// RunTx runs fn in a new transaction, committing if it returns nil and rolling back otherwise.
+func RunTx(journal string, fn func(tx *Tx) error) error {
+	tx, err := BeginTx(journal)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return errors.Join(err, fmt.Errorf("rollback: %w", rbErr))
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// TxCreate creates obj and records it in tx.
+func TxCreate[T KoiObject](tx *Tx, obj T) (T, error) {
+	if err := tx.check(); err != nil {
+		return obj, err
+	}
+	created, err := Create(obj)
+	if err != nil {
+		return created, err
+	}
+	return created, tx.record(&TxEntry{Op: TxCreated, Kind: kindForPath(created.IRI()), IRI: created.IRI()})
+}
+
+// TxPatch snapshots the fields of obj that the patch sends, as stored on the server, patches it and records
+// the snapshot in tx. Image and file uploads cannot be snapshotted and are not undone.
+func TxPatch[T KoiObject](tx *Tx, obj T) (T, error) {
+	if err := tx.check(); err != nil {
+		return obj, err
+	}
+	before, err := Get(obj)
+	if err != nil {
+		return obj, fmt.Errorf("snapshotting %s: %w", obj.IRI(), err)
+	}
+	fields, err := patchSnapshot(GetClient(), before, obj)
+	if err != nil {
+		return obj, fmt.Errorf("snapshotting %s: %w", obj.IRI(), err)
+	}
+	snapshot, err := json.Marshal(fields)
+	if err != nil {
+		return obj, fmt.Errorf("snapshotting %s: %w", obj.IRI(), err)
+	}
+	patched, err := Patch(obj)
+	if err != nil {
+		return patched, err
+	}
+	return patched, tx.record(&TxEntry{Op: TxPatched, Kind: kindForPath(obj.IRI()), IRI: obj.IRI(), Snapshot: snapshot})
+}
+
+// txRelations maps write-only fields that the server does not return to the sub-resources that list them.
+var txRelations = map[string]string{
+	"tags":         "/tags",
+	"relatedItems": "/related_items",
+}
+
+// patchSnapshot returns, keyed by JSON name, the values on before of the writable fields that patching obj sends,
+// so that patching them back undoes the patch. Empty strings and other unset values are recorded as null, which
+// clears them, rather than left out the way omitempty would; write-only relations such as an item's tags are
+// read through their sub-resources.
+func patchSnapshot(c *koiClient, before, obj KoiObject) (map[string]any, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var sent map[string]json.RawMessage
+	if err := json.Unmarshal(data, &sent); err != nil {
+		return nil, err
+	}
+	snapshot := map[string]any{}
+	bv := reflect.ValueOf(before).Elem()
+	for i := 0; i < bv.NumField(); i++ {
+		field := bv.Type().Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if _, ok := sent[name]; !ok || !field.IsExported() || strings.HasPrefix(name, "@") {
+			continue
+		}
+		switch field.Tag.Get("access") {
+		case "rw":
+			snapshot[name] = snapshotValue(bv.Field(i))
+		case "wo":
+			rel, ok := txRelations[name]
+			if !ok {
+				continue // Uploads and flags such as deleteImage
+			}
+			var have []*struct {
+				IRI string `json:"@id"`
+			}
+			if err := c.listResources(before.IRI()+rel, &have); err != nil {
+				return nil, fmt.Errorf("listing %s: %w", name, err)
+			}
+			iris := make([]string, 0, len(have))
+			for _, h := range have {
+				iris = append(iris, h.IRI)
+			}
+			snapshot[name] = iris
+		}
+	}
+	return snapshot, nil
+}
+
+// snapshotValue returns v to be sent back as is, or the value that clears the field if v is empty.
+func snapshotValue(v reflect.Value) any {
+	if !v.IsZero() {
+		return v.Interface()
+	}
+	switch v.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return v.Interface()
+	case reflect.Slice:
+		return []any{}
+	}
+	return nil
+}
+
+// Entries returns the recorded changes in the order they were made.
+func (tx *Tx) Entries() []*TxEntry {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	return append([]*TxEntry(nil), tx.entries...)
+}
+
+// Commit ends the transaction, keeping its changes, and removes the journal.
+func (tx *Tx) Commit() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	tx.closed = true
+	tx.entries = nil
+	return tx.removeJournal()
+}
+
+// Rollback undoes the recorded changes in reverse order: created objects are deleted and patched objects are
+// restored from their snapshots. Objects already gone count as undone. On failure the remaining entries stay
+// in the journal.
+func (tx *Tx) Rollback() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	tx.closed = true
+	c := GetClient()
+	for len(tx.entries) > 0 {
+		e := tx.entries[len(tx.entries)-1]
+		if err := e.undo(c); err != nil {
+			return err
+		}
+		tx.entries = tx.entries[:len(tx.entries)-1]
+		if err := tx.save(); err != nil {
+			return err
+		}
+	}
+	return tx.removeJournal()
+}
+
+// ResumeRollback finishes the rollback of a transaction from its journal, e.g. after a crash.
+func ResumeRollback(journal string) error {
+	data, err := os.ReadFile(journal)
+	if err != nil {
+		return fmt.Errorf("reading journal %s: %w", journal, err)
+	}
+	tx := &Tx{journal: journal}
+	if err := json.Unmarshal(data, &tx.entries); err != nil {
+		return fmt.Errorf("decoding journal %s: %w", journal, err)
+	}
+	return tx.Rollback()
+}
+
+// Summary
+func (e *TxEntry) Summary() string {
+	return fmt.Sprintf("%-8s %-12s %s", e.Op, e.Kind, e.IRI)
+}
+
+func (e *TxEntry) undo(c *koiClient) error {
+	var err error
+	switch e.Op {
+	case TxCreated:
+		err = c.deleteResource(e.IRI)
+	case TxPatched:
+		var out json.RawMessage
+		err = c.patchResource(e.IRI, e.Snapshot, &out)
+	default:
+		return fmt.Errorf("unknown journal operation %q for %s", e.Op, e.IRI)
+	}
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("undoing %s of %s: %w", e.Op, e.IRI, err)
+	}
+	return nil
+}
+
+func (tx *Tx) check() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.closed {
+		return fmt.Errorf("transaction already committed or rolled back")
+	}
+	return nil
+}
+
+func (tx *Tx) record(e *TxEntry) error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	tx.entries = append(tx.entries, e)
+	return tx.save()
+}
+
+func (tx *Tx) save() error {
+	if tx.journal == "" {
+		return nil
+	}
+	entries := tx.entries
+	if entries == nil {
+		entries = []*TxEntry{}
+	}
+	return writeJSONFile(tx.journal, entries)
+}
+
+func (tx *Tx) removeJournal() error {
+	if tx.journal == "" {
+		return nil
+	}
+	if err := os.Remove(tx.journal); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing journal %s: %w", tx.journal, err)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package koiApi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// txServer serves one stored item with a tag, no related items and no visibility of its own, and records the bodies of the patches it receives.
+func txServer(t *testing.T) (patches *[]map[string]any) {
+	t.Helper()
+	var mu sync.Mutex
+	patches = &[]map[string]any{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/ld+json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/items/1":
+			io.WriteString(w, `{"@id":"/api/items/1","id":"1","name":"Dune","quantity":1,"collection":"/api/collections/1"}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/items/1/tags":
+			if r.URL.Query().Get("page") != "1" {
+				io.WriteString(w, `[]`)
+				return
+			}
+			io.WriteString(w, `[{"@id":"/api/tags/5","id":"5","label":"signed"}]`)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/items/1/related_items":
+			io.WriteString(w, `[]`)
+		case r.Method == http.MethodPatch && r.URL.Path == "/api/items/1":
+			var body map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("decoding patch: %v", err)
+			}
+			mu.Lock()
+			*patches = append(*patches, body)
+			mu.Unlock()
+			io.WriteString(w, `{"@id":"/api/items/1","id":"1"}`)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	defaultClient = NewKoiClient(srv.URL, time.Second)
+	t.Cleanup(func() { defaultClient = nil })
+	return patches
+}
+
+func TestTxPatchRollback(t *testing.T) {
+	patches := txServer(t)
+	tx, err := BeginTx("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Tags are write-only, so the stored ones must come from the tags sub-resource.
+	item := &Item{ID: "1", Name: "Dune Messiah", Quantity: 2, Collection: "/api/collections/1", Tags: []string{"/api/tags/7"}}
+	if _, err := TxPatch(tx, item); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if len(*patches) != 2 {
+		t.Fatalf("got %d patches, want the patch and its undo", len(*patches))
+	}
+	want := map[string]any{
+		"name":       "Dune",
+		"quantity":   float64(1),
+		"collection": "/api/collections/1",
+		"tags":       []any{"/api/tags/5"},
+	}
+	if undo := (*patches)[1]; !reflect.DeepEqual(undo, want) {
+		t.Errorf("undo patch = %v, want %v", undo, want)
+	}
+}
+
+func TestTxPatchRollbackClearsEmptyFields(t *testing.T) {
+	patches := txServer(t)
+	tx, _ := BeginTx("")
+	// The item had no visibility and no related items, so undoing must clear them rather than leave them out.
+	item := &Item{ID: "1", Name: "Dune", Quantity: 1, Collection: "/api/collections/1", Visibility: VisibilityPrivate, RelatedItems: []string{"/api/items/2"}}
+	if _, err := TxPatch(tx, item); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	undo := (*patches)[1]
+	if v, ok := undo["visibility"]; !ok || v != nil {
+		t.Errorf("undo patch = %v, want visibility null", undo)
+	}
+	if v, ok := undo["relatedItems"]; !ok || !reflect.DeepEqual(v, []any{}) {
+		t.Errorf("undo patch = %v, want relatedItems empty", undo)
+	}
+}
+
+func TestPatchSnapshotNull(t *testing.T) {
+	before := &Tag{ID: "1", Label: "signed"}
+	got, err := patchSnapshot(nil, before, &Tag{ID: "1", Label: "signed", Description: "by the author", Visibility: VisibilityPrivate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(got)
+	for _, want := range []string{`"description":null`, `"visibility":null`, `"label":"signed"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("snapshot %s lacks %s", data, want)
+		}
+	}
+	if _, ok := got["id"]; ok {
+		t.Errorf("snapshot %s holds the read-only id", data)
+	}
+}
@@ -0,0 +1,199 @@
+package koiApi
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+)
+
+// UpsertAction says what Upsert did.
+type UpsertAction string
+
+const (
+	UpsertCreated   UpsertAction = "created"
+	UpsertUpdated   UpsertAction = "updated"
+	UpsertUnchanged UpsertAction = "unchanged"
+)
+
+// UpsertResult reports the outcome of an Upsert.
+type UpsertResult struct {
+	Action UpsertAction
+	Fields []string // JSON names of the fields patched on update
+}
+
+// KeyFunc finds the stored object with the same natural key as obj, reporting false when there is none.
+type KeyFunc[T KoiObject] func(ctx context.Context, c *koiClient, obj T) (T, bool, error)
+
+// KeyBy builds a KeyFunc that lists objects of obj's type with the given server-side filters ("field=value")
+// and returns the first one match accepts. The server filters are only a narrowing hint; match decides.
+func KeyBy[T KoiObject](filters func(obj T) []string, match func(candidate, obj T) bool) KeyFunc[T] {
+	return func(ctx context.Context, c *koiClient, obj T) (T, bool, error) {
+		var zero T
+		if err := ctx.Err(); err != nil {
+			return zero, false, err
+		}
+		var candidates []T
+		if err := c.listResources(baseObjPath(obj), &candidates, filters(obj)...); err != nil {
+			return zero, false, err
+		}
+		for _, candidate := range candidates {
+			if match(candidate, obj) {
+				return candidate, true, nil
+			}
+		}
+		return zero, false, nil
+	}
+}
+
+// CollectionByTitle matches a collection by Title within the same Parent.
+func CollectionByTitle() KeyFunc[*Collection] {
+	return KeyBy(
+		func(o *Collection) []string { return []string{"title=" + o.Title} },
+		func(a, o *Collection) bool { return a.Title == o.Title && a.Parent == o.Parent },
+	)
+}
+
+// TagByLabel matches a tag by Label.
+func TagByLabel() KeyFunc[*Tag] {
+	return KeyBy(
+		func(o *Tag) []string { return []string{"label=" + o.Label} },
+		func(a, o *Tag) bool { return a.Label == o.Label },
+	)
+}
+
+// ChoiceListByName matches a choice list by Name.
+func ChoiceListByName() KeyFunc[*ChoiceList] {
+	return KeyBy(
+		func(o *ChoiceList) []string { return []string{"name=" + o.Name} },
+		func(a, o *ChoiceList) bool { return a.Name == o.Name },
+	)
+}
+
+// ItemByDatum matches an item through a datum with the given label and value, such as an ISBN. When the item
+// has a Collection, only items in that collection match.
+func ItemByDatum(label, value string) KeyFunc[*Item] {
+	return func(ctx context.Context, c *koiClient, obj *Item) (*Item, bool, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, false, err
+		}
+		var data []*Datum
+		if err := c.listResources(baseObjPath(&Datum{}), &data, "label="+label, "value="+value); err != nil {
+			return nil, false, err
+		}
+		for _, d := range data {
+			if d.Label != label || d.Value != value || d.Item == "" {
+				continue
+			}
+			var item Item
+			if err := c.getResource(d.Item, &item); err != nil {
+				return nil, false, err
+			}
+			if obj.Collection == "" || item.Collection == obj.Collection {
+				return &item, true, nil
+			}
+		}
+		return nil, false, nil
+	}
+}
+
+// Upsert creates obj unless key finds an existing object, in which case the writable fields that are set on
+// obj and differ from the stored object are patched; an item's tags and related items are compared as sets.
+// Fields left empty on obj are never sent, so Upsert cannot clear a stored value; patch the field to do that.
+// A nil client means GetClient(). The returned object is the created, patched or unchanged stored object.
+func Upsert[T KoiObject](ctx context.Context, c *koiClient, obj T, key KeyFunc[T]) (T, UpsertResult, error) {
+	if c == nil {
+		c = GetClient()
+	}
+	existing, found, err := key(ctx, c, obj)
+	if err != nil {
+		return obj, UpsertResult{}, fmt.Errorf("looking up %T: %w", obj, err)
+	}
+	if err := ctx.Err(); err != nil {
+		return obj, UpsertResult{}, err
+	}
+
+	if !found {
+		if err := obj.Validate(); err != nil {
+			return obj, UpsertResult{}, err
+		}
+		created := reflect.New(reflect.TypeOf(obj).Elem()).Interface().(T)
+		if err := c.createResource(ctx, baseObjPath(obj), obj, obj, created); err != nil {
+			return obj, UpsertResult{}, err
+		}
+		return created, UpsertResult{Action: UpsertCreated}, nil
+	}
+
+	changes := differingFields(existing, obj)
+	if item, ok := any(obj).(*Item); ok {
+		if err := differingRelations(c, existing.IRI(), item, changes); err != nil {
+			return existing, UpsertResult{}, err
+		}
+	}
+	if len(changes) == 0 {
+		return existing, UpsertResult{Action: UpsertUnchanged}, nil
+	}
+	patched := reflect.New(reflect.TypeOf(obj).Elem()).Interface().(T)
+	if err := c.patchResource(existing.IRI(), changes, patched); err != nil {
+		return existing, UpsertResult{}, err
+	}
+	return patched, UpsertResult{Action: UpsertUpdated, Fields: sortedKeys(changes)}, nil
+}
+
+// differingFields returns, keyed by JSON name, the writable fields that are set on want and differ on have.
+// Write-only fields are skipped: the server never returns them, so they would always look changed.
+func differingFields[T KoiObject](have, want T) map[string]any {
+	changes := map[string]any{}
+	hv := reflect.ValueOf(have).Elem()
+	wv := reflect.ValueOf(want).Elem()
+	for i := 0; i < wv.NumField(); i++ {
+		field := wv.Type().Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if !field.IsExported() || name == "" || name == "-" || strings.HasPrefix(name, "@") {
+			continue
+		}
+		if field.Tag.Get("access") != "rw" {
+			continue
+		}
+		if wv.Field(i).IsZero() || reflect.DeepEqual(wv.Field(i).Interface(), hv.Field(i).Interface()) {
+			continue
+		}
+		changes[name] = wv.Field(i).Interface()
+	}
+	return changes
+}
+
+// differingRelations adds the tags and related items set on want to changes when they differ from those of the
+// stored item at iri. Both are write-only fields, so they are compared through their sub-resources.
+func differingRelations(c *koiClient, iri string, want *Item, changes map[string]any) error {
+	for _, rel := range []struct {
+		name, path string
+		want       []string
+	}{
+		{"tags", "/tags", want.Tags},
+		{"relatedItems", "/related_items", want.RelatedItems},
+	} {
+		if len(rel.want) == 0 {
+			continue
+		}
+		var have []*struct {
+			ID ID `json:"id"`
+		}
+		if err := c.listResources(iri+rel.path, &have); err != nil {
+			return fmt.Errorf("listing %s of %s: %w", rel.name, iri, err)
+		}
+		wantIDs := map[ID]bool{}
+		for _, w := range rel.want {
+			wantIDs[ID(path.Base(w))] = true
+		}
+		same := len(have) == len(wantIDs)
+		for _, h := range have {
+			same = same && wantIDs[h.ID]
+		}
+		if !same {
+			changes[rel.name] = rel.want
+		}
+	}
+	return nil
+}
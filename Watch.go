@@ -0,0 +1,262 @@
+package koiApi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// EventType says what happened to an object.
+type EventType string
+
+const (
+	EventCreated EventType = "created"
+	EventUpdated EventType = "updated"
+	EventDeleted EventType = "deleted"
+)
+
+// Event is a change seen by Watch.
+type Event struct {
+	Type   EventType
+	Kind   string    // basePathForType key, e.g. item
+	IRI    string    // Object IRI
+	Object KoiObject // Typed object, e.g. *Item; nil for deletions
+	At     time.Time // When the change happened
+	Log    *Log      // Log entry of a deletion
+}
+
+// Summary
+func (e *Event) Summary() string {
+	return fmt.Sprintf("%-8s %-12s %s", e.Type, e.Kind, e.IRI)
+}
+
+// WatchOptions configures Watch.
+type WatchOptions struct {
+	Interval   time.Duration // Time between polls; defaults to 30 seconds
+	Kinds      []string      // basePathForType keys to watch; defaults to every kind with timestamps
+	CursorFile string        // JSON file holding the position between runs; empty keeps it in memory
+	Client     *koiClient    // Defaults to GetClient()
+	OnError    func(error)   // Called when a poll fails; polling continues
+}
+
+// WatchCursor is the position of a watcher, persisted to WatchOptions.CursorFile.
+type WatchCursor struct {
+	Changed map[string]time.Time `json:"changed"` // Latest createdAt/updatedAt seen, by kind
+	Logged  time.Time            `json:"logged"`  // Latest loggedAt of a deletion seen
+	Seen    map[string]time.Time `json:"seen"`    // Timestamp last emitted for each object or deletion log IRI at the cursor boundary
+}
+
+// Watch polls the server for changes and emits an event for each created, updated or deleted object until
+// ctx ends, then closes the channel. Without a saved cursor it starts from now rather than replaying history.
+// Changes are detected through createdAt[after] and updatedAt[after] filters and deletions through /api/logs.
+func Watch(ctx context.Context, opts WatchOptions) <-chan Event {
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		w := newWatcher(opts)
+		ticker := time.NewTicker(w.opts.Interval)
+		defer ticker.Stop()
+		for {
+			if err := w.poll(ctx, events); err != nil && ctx.Err() == nil && w.opts.OnError != nil {
+				w.opts.OnError(err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return events
+}
+
+type watcher struct {
+	opts   WatchOptions
+	cursor *WatchCursor
+}
+
+func newWatcher(opts WatchOptions) *watcher {
+	if opts.Interval <= 0 {
+		opts.Interval = 30 * time.Second
+	}
+	if len(opts.Kinds) == 0 {
+		for kind, timestamped := range cachedKinds {
+			if timestamped {
+				opts.Kinds = append(opts.Kinds, kind)
+			}
+		}
+		sort.Strings(opts.Kinds)
+	}
+	if opts.Client == nil {
+		opts.Client = GetClient()
+	}
+	w := &watcher{opts: opts}
+	if data, err := os.ReadFile(opts.CursorFile); err == nil && json.Unmarshal(data, &w.cursor) == nil && w.cursor != nil {
+		if w.cursor.Changed == nil {
+			w.cursor.Changed = map[string]time.Time{}
+		}
+		if w.cursor.Seen == nil {
+			w.cursor.Seen = map[string]time.Time{}
+		}
+		return w
+	}
+	now := time.Now()
+	w.cursor = &WatchCursor{Changed: map[string]time.Time{}, Logged: now, Seen: map[string]time.Time{}}
+	for _, kind := range opts.Kinds {
+		w.cursor.Changed[kind] = now
+	}
+	return w
+}
+
+// poll emits the changes since the cursor and advances it.
+func (w *watcher) poll(ctx context.Context, events chan<- Event) error {
+	c := w.opts.Client
+	for _, kind := range w.opts.Kinds {
+		since, ok := w.cursor.Changed[kind]
+		if !ok {
+			since = time.Now()
+			w.cursor.Changed[kind] = since
+			continue
+		}
+		changed := map[string]json.RawMessage{}
+		for _, field := range []string{"createdAt", "updatedAt"} {
+			var objs []json.RawMessage
+			if err := c.listResources(basePathForType[kind], &objs, field+"[after]="+since.Format(time.RFC3339)); err != nil {
+				return fmt.Errorf("polling %s: %w", kind, err)
+			}
+			for _, raw := range objs {
+				if iri := rawIRI(kind, raw); iri != "" {
+					changed[iri] = raw
+				}
+			}
+		}
+		latest := since
+		for _, iri := range sortedKeys(changed) {
+			raw := changed[iri]
+			at := rawModifiedAt(raw)
+			if at.Before(since) || w.cursor.Seen[iri].Equal(at) {
+				continue // Boundary objects are returned again by inclusive filters
+			}
+			obj := newForKind(kind)
+			if obj == nil || json.Unmarshal(raw, obj) != nil {
+				continue
+			}
+			typ := EventUpdated
+			if created := rawCreatedAt(raw); !created.Before(since) && created.Equal(at) {
+				typ = EventCreated
+			}
+			if !emitEvent(ctx, events, Event{Type: typ, Kind: kind, IRI: iri, Object: obj, At: at}) {
+				return ctx.Err()
+			}
+			w.cursor.Seen[iri] = at
+			if at.After(latest) {
+				latest = at
+			}
+		}
+		w.cursor.Changed[kind] = latest
+	}
+
+	// Deleted only returns entries strictly after its argument, so it is asked for one instant earlier: a
+	// deletion logged in the same second as the last one seen, but after that poll, must still be found.
+	since := w.cursor.Logged
+	if !since.IsZero() {
+		since = since.Add(-time.Nanosecond)
+	}
+	deleted, err := Deleted(c, since)
+	if err != nil {
+		return err
+	}
+	for _, l := range deleted {
+		if l.LoggedAt.Before(w.cursor.Logged) || !w.cursor.Seen[l.IRI()].IsZero() {
+			continue // Entries at the boundary are returned again by the inclusive filter
+		}
+		if w.watches(l.Kind()) && !emitEvent(ctx, events, Event{Type: EventDeleted, Kind: l.Kind(), IRI: l.ObjectIRI(), At: l.LoggedAt, Log: l}) {
+			return ctx.Err()
+		}
+		w.cursor.Seen[l.IRI()] = l.LoggedAt
+		if l.LoggedAt.After(w.cursor.Logged) {
+			w.cursor.Logged = l.LoggedAt
+		}
+	}
+
+	// Only entries at a kind's cursor, or deletions at the log cursor, can be returned again.
+	for iri, at := range w.cursor.Seen {
+		cursor := w.cursor.Changed[kindForPath(iri)]
+		if kindForPath(iri) == "log" {
+			cursor = w.cursor.Logged
+		}
+		if at.Before(cursor) {
+			delete(w.cursor.Seen, iri)
+		}
+	}
+	if w.opts.CursorFile != "" {
+		return writeJSONFile(w.opts.CursorFile, w.cursor)
+	}
+	return nil
+}
+
+func (w *watcher) watches(kind string) bool {
+	for _, k := range w.opts.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func emitEvent(ctx context.Context, events chan<- Event, e Event) bool {
+	select {
+	case events <- e:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func rawCreatedAt(raw json.RawMessage) time.Time {
+	var ts struct {
+		CreatedAt time.Time `json:"createdAt"`
+	}
+	json.Unmarshal(raw, &ts)
+	return ts.CreatedAt
+}
+
+// newForKind returns a new, empty object for a basePathForType key.
+func newForKind(kind string) KoiObject {
+	switch kind {
+	case "album":
+		return &Album{}
+	case "choicelist":
+		return &ChoiceList{}
+	case "collection":
+		return &Collection{}
+	case "datum":
+		return &Datum{}
+	case "field":
+		return &Field{}
+	case "item":
+		return &Item{}
+	case "loan":
+		return &Loan{}
+	case "log":
+		return &Log{}
+	case "photo":
+		return &Photo{}
+	case "tag":
+		return &Tag{}
+	case "tagcategory":
+		return &TagCategory{}
+	case "template":
+		return &Template{}
+	case "user":
+		return &User{}
+	case "wish":
+		return &Wish{}
+	case "wishlist":
+		return &Wishlist{}
+	}
+	return nil
+}
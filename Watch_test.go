@@ -0,0 +1,66 @@
+package koiApi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatchDeletionsAtTheCursor(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	var mu sync.Mutex
+	var logs []string
+	deleteLog := func(id string) {
+		mu.Lock()
+		defer mu.Unlock()
+		logs = append(logs, fmt.Sprintf(`{"@id":"/api/logs/%s","id":"%s","type":"delete","loggedAt":"%s","objectId":"%s","objectClass":"App\\\\Entity\\\\Item"}`, id, id, at.Format(time.RFC3339), id))
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/ld+json")
+		if r.URL.Path != "/api/logs" || r.URL.Query().Get("page") != "1" {
+			io.WriteString(w, `[]`)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		io.WriteString(w, "["+strings.Join(logs, ",")+"]")
+	}))
+	defer srv.Close()
+
+	w := newWatcher(WatchOptions{Kinds: []string{"item"}, Client: NewKoiClient(srv.URL, time.Second)})
+	w.cursor.Logged = at.Add(-time.Minute)
+	poll := func() []string {
+		events := make(chan Event, 10)
+		if err := w.poll(context.Background(), events); err != nil {
+			t.Fatal(err)
+		}
+		close(events)
+		var iris []string
+		for e := range events {
+			iris = append(iris, e.IRI)
+		}
+		return iris
+	}
+
+	deleteLog("1")
+	if got := poll(); len(got) != 1 || got[0] != "/api/items/1" {
+		t.Fatalf("first poll = %v", got)
+	}
+	// A second deletion in the same second, logged after the first poll.
+	deleteLog("2")
+	if got := poll(); len(got) != 1 || got[0] != "/api/items/2" {
+		t.Errorf("second poll = %v, want only the new deletion", got)
+	}
+	if got := poll(); len(got) != 0 {
+		t.Errorf("third poll = %v, want nothing", got)
+	}
+	if !w.cursor.Logged.Equal(at) {
+		t.Errorf("cursor at %s, want %s", w.cursor.Logged, at)
+	}
+}
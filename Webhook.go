@@ -0,0 +1,349 @@
+package koiApi
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Headers set on every webhook delivery.
+const (
+	WebhookSignatureHeader = "X-Koi-Signature" // sha256=<hex HMAC of "<timestamp>.<body>">, when the target has a Secret
+	WebhookTimestampHeader = "X-Koi-Timestamp" // Unix seconds at which the attempt was signed
+	WebhookEventHeader     = "X-Koi-Event"     // EventType
+	WebhookDeliveryHeader  = "X-Koi-Delivery"  // WebhookPayload.ID, identical across retries
+)
+
+// WebhookTarget is a URL that receives change events, with the filters that select them.
+type WebhookTarget struct {
+	URL          string
+	Secret       string       // Key for the signature header; empty sends unsigned requests
+	Kinds        []string     // basePathForType keys to deliver; empty delivers all
+	Types        []EventType  // Event types to deliver; empty delivers all
+	Collection   string       // Collection IRI; only objects in its subtree are delivered
+	Visibilities []Visibility // Only objects with one of these FinalVisibility values are delivered
+}
+
+// WebhookOptions configures RunWebhooks.
+type WebhookOptions struct {
+	Targets        []WebhookTarget
+	Watch          WatchOptions  // Where and how often to poll for changes
+	MaxAttempts    int           // Attempts per delivery; defaults to 5
+	Backoff        time.Duration // Delay before the first retry, doubled after each attempt; defaults to 1 second
+	MaxBackoff     time.Duration // Longest delay between retries; defaults to 5 minutes
+	DeadLetterFile string        // JSONL file receiving deliveries that ran out of attempts; empty drops them
+	HTTPClient     *http.Client  // Defaults to a client with a 10 second timeout
+}
+
+// WebhookPayload is the JSON body POSTed to a target.
+type WebhookPayload struct {
+	ID     string    `json:"id"`   // Stable identifier of the event, for deduplication by the receiver
+	Type   EventType `json:"type"` // created, updated or deleted
+	Kind   string    `json:"kind"` // basePathForType key, e.g. item
+	IRI    string    `json:"iri"`
+	At     time.Time `json:"at"`
+	Object KoiObject `json:"object,omitempty"` // Absent for deletions
+}
+
+// DeadLetter is one line of WebhookOptions.DeadLetterFile.
+type DeadLetter struct {
+	URL      string          `json:"url"`
+	Payload  json.RawMessage `json:"payload"`
+	Attempts int             `json:"attempts"`
+	Error    string          `json:"error"`
+	At       time.Time       `json:"at"`
+}
+
+// SignWebhook returns the signature header value for a body sent at timestamp (Unix seconds) with secret.
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhook reports whether signature is valid for the body and timestamp, for use by receivers.
+func VerifyWebhook(secret, timestamp, signature string, body []byte) bool {
+	return hmac.Equal([]byte(SignWebhook(secret, timestamp, body)), []byte(signature))
+}
+
+// RunWebhooks watches for changes and POSTs each matching event to every target until ctx ends. Each target
+// has its own queue, so a slow or failing receiver does not hold up the others. Failed deliveries are retried
+// with exponential backoff and, once out of attempts or interrupted by shutdown, appended to the dead-letter
+// file. Deletions pass the collection and visibility filters if the object was delivered to the target before.
+func RunWebhooks(ctx context.Context, opts WebhookOptions) error {
+	if len(opts.Targets) == 0 {
+		return fmt.Errorf("no webhook targets: %w", ErrInvalidInput)
+	}
+	for _, t := range opts.Targets {
+		if u, err := url.Parse(t.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("webhook URL %q: %w", t.URL, ErrInvalidInput)
+		}
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = 5 * time.Minute
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	if opts.Watch.Client == nil {
+		opts.Watch.Client = GetClient()
+	}
+
+	d := &dispatcher{opts: opts, parents: map[string]string{}, itemCollections: map[string]string{}}
+	var wg sync.WaitGroup
+	queues := make([]chan WebhookPayload, len(opts.Targets))
+	delivered := make([]map[string]bool, len(opts.Targets))
+	for i := range opts.Targets {
+		queues[i] = make(chan WebhookPayload, 100)
+		delivered[i] = map[string]bool{}
+		wg.Add(1)
+		go func(t *WebhookTarget, queue <-chan WebhookPayload) {
+			defer wg.Done()
+			for p := range queue {
+				d.deliver(ctx, t, p)
+			}
+		}(&opts.Targets[i], queues[i])
+	}
+
+	for e := range Watch(ctx, opts.Watch) {
+		d.observe(e)
+		p := WebhookPayload{ID: eventID(e), Type: e.Type, Kind: e.Kind, IRI: e.IRI, At: e.At, Object: e.Object}
+		for i := range opts.Targets {
+			if !d.matches(&opts.Targets[i], e, delivered[i]) {
+				continue
+			}
+			if e.Type == EventDeleted {
+				delete(delivered[i], e.IRI)
+			} else {
+				delivered[i][e.IRI] = true
+			}
+			select {
+			case queues[i] <- p:
+			case <-ctx.Done():
+				d.deadLetter(&opts.Targets[i], p, 0, ctx.Err())
+			}
+		}
+	}
+	for _, q := range queues {
+		close(q)
+	}
+	wg.Wait()
+	return nil
+}
+
+type dispatcher struct {
+	opts            WebhookOptions
+	mu              sync.Mutex        // Serializes dead-letter writes
+	parents         map[string]string // Collection IRI to parent IRI
+	itemCollections map[string]string // Item IRI to collection IRI
+}
+
+// observe keeps the collection tree and item locations up to date from the events themselves.
+func (d *dispatcher) observe(e Event) {
+	switch obj := e.Object.(type) {
+	case *Collection:
+		d.parents[e.IRI] = obj.Parent
+	case *Item:
+		d.itemCollections[e.IRI] = obj.Collection
+	}
+	if e.Type == EventDeleted {
+		delete(d.parents, e.IRI)
+		delete(d.itemCollections, e.IRI)
+	}
+}
+
+func (d *dispatcher) matches(t *WebhookTarget, e Event, delivered map[string]bool) bool {
+	if len(t.Kinds) > 0 && !slices.Contains(t.Kinds, e.Kind) {
+		return false
+	}
+	if len(t.Types) > 0 && !slices.Contains(t.Types, e.Type) {
+		return false
+	}
+	if e.Type == EventDeleted {
+		return (t.Collection == "" && len(t.Visibilities) == 0) || delivered[e.IRI]
+	}
+	if len(t.Visibilities) > 0 {
+		v, ok := objectVisibility(e.Object)
+		if !ok || !slices.Contains(t.Visibilities, v) {
+			return false
+		}
+	}
+	if t.Collection != "" {
+		col := d.collectionOf(e.Object)
+		if col == "" || !d.inSubtree(col, t.Collection) {
+			return false
+		}
+	}
+	return true
+}
+
+// collectionOf returns the IRI of the collection holding obj, itself for a collection, or "" if it has none.
+func (d *dispatcher) collectionOf(obj KoiObject) string {
+	switch o := obj.(type) {
+	case *Collection:
+		return o.IRI()
+	case *Item:
+		return o.Collection
+	case *Datum:
+		if o.Collection != "" {
+			return o.Collection
+		}
+		return d.itemCollection(o.Item)
+	case *Loan:
+		return d.itemCollection(o.Item)
+	}
+	return ""
+}
+
+func (d *dispatcher) itemCollection(iri string) string {
+	if iri == "" {
+		return ""
+	}
+	if col, ok := d.itemCollections[iri]; ok {
+		return col
+	}
+	var item Item
+	if err := d.opts.Watch.Client.getResource(iri, &item); err != nil {
+		return ""
+	}
+	d.itemCollections[iri] = item.Collection
+	return item.Collection
+}
+
+// inSubtree reports whether the collection is root or one of its descendants, fetching unknown parents.
+func (d *dispatcher) inSubtree(iri, root string) bool {
+	seen := map[string]bool{}
+	for iri != "" && !seen[iri] {
+		if iri == root {
+			return true
+		}
+		seen[iri] = true
+		parent, ok := d.parents[iri]
+		if !ok {
+			var col Collection
+			if err := d.opts.Watch.Client.getResource(iri, &col); err != nil {
+				return false
+			}
+			parent = col.Parent
+			d.parents[iri] = parent
+		}
+		iri = parent
+	}
+	return false
+}
+
+// deliver POSTs p to t, retrying with backoff, and dead-letters it if every attempt fails.
+func (d *dispatcher) deliver(ctx context.Context, t *WebhookTarget, p WebhookPayload) {
+	body, err := json.Marshal(p)
+	if err != nil {
+		d.deadLetter(t, p, 0, err)
+		return
+	}
+	delay := d.opts.Backoff
+	for attempt := 1; ; attempt++ {
+		err = d.post(ctx, t, p, body)
+		if err == nil {
+			return
+		}
+		if attempt >= d.opts.MaxAttempts || ctx.Err() != nil {
+			d.deadLetter(t, p, attempt, err)
+			return
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			d.deadLetter(t, p, attempt, err)
+			return
+		}
+		delay = min(delay*2, d.opts.MaxBackoff)
+	}
+}
+
+func (d *dispatcher) post(ctx context.Context, t *WebhookTarget, p WebhookPayload, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookEventHeader, string(p.Type))
+	req.Header.Set(WebhookDeliveryHeader, p.ID)
+	if t.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhook(t.Secret, timestamp, body))
+	}
+	resp, err := d.opts.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s responded %s", t.URL, resp.Status)
+	}
+	return nil
+}
+
+func (d *dispatcher) deadLetter(t *WebhookTarget, p WebhookPayload, attempts int, cause error) {
+	if d.opts.DeadLetterFile == "" {
+		return
+	}
+	payload, _ := json.Marshal(p)
+	line, err := json.Marshal(DeadLetter{URL: t.URL, Payload: payload, Attempts: attempts, Error: cause.Error(), At: time.Now()})
+	if err != nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	f, err := os.OpenFile(d.opts.DeadLetterFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		if d.opts.Watch.OnError != nil {
+			d.opts.Watch.OnError(fmt.Errorf("writing dead letter: %w", err))
+		}
+		return
+	}
+	defer f.Close()
+	f.Write(append(line, '\n'))
+}
+
+// eventID derives a stable identifier from what changed and when.
+func eventID(e Event) string {
+	sum := sha256.Sum256([]byte(string(e.Type) + " " + e.IRI + " " + e.At.UTC().Format(time.RFC3339Nano)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// objectVisibility returns the FinalVisibility of obj, falling back to Visibility for types without one.
+func objectVisibility(obj KoiObject) (Visibility, bool) {
+	if obj == nil {
+		return "", false
+	}
+	v := reflect.ValueOf(obj)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	for _, name := range []string{"FinalVisibility", "Visibility"} {
+		if f := v.FieldByName(name); f.IsValid() && f.Type() == reflect.TypeOf(Visibility("")) && f.String() != "" {
+			return Visibility(f.String()), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,147 @@
+package koiApi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSignWebhook(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	// Computed independently with: printf '%s' '1700000000.{"id":"1"}' | openssl dgst -sha256 -hmac s3cret
+	want := "sha256=2b9dee6c893e4bf012ad34ee7b89d492b9567b4f47740ccbf0f161ba3717dc08"
+	if got := SignWebhook("s3cret", "1700000000", body); got != want {
+		t.Errorf("SignWebhook = %s, want %s", got, want)
+	}
+	if !VerifyWebhook("s3cret", "1700000000", want, body) {
+		t.Error("VerifyWebhook rejected a valid signature")
+	}
+	for name, ok := range map[string]bool{
+		"wrong secret":    VerifyWebhook("other", "1700000000", want, body),
+		"wrong timestamp": VerifyWebhook("s3cret", "1700000001", want, body),
+		"changed body":    VerifyWebhook("s3cret", "1700000000", want, []byte(`{"id":"2"}`)),
+		"missing prefix":  VerifyWebhook("s3cret", "1700000000", want[len("sha256="):], body),
+		"empty signature": VerifyWebhook("s3cret", "1700000000", "", body),
+	} {
+		if ok {
+			t.Errorf("VerifyWebhook accepted a signature with a %s", name)
+		}
+	}
+}
+
+func TestWebhookMatches(t *testing.T) {
+	d := &dispatcher{
+		parents: map[string]string{
+			"/api/collections/root":  "",
+			"/api/collections/child": "/api/collections/root",
+			"/api/collections/other": "",
+		},
+		itemCollections: map[string]string{},
+	}
+	inChild := &Item{ID: "1", Collection: "/api/collections/child", FinalVisibility: VisibilityPublic}
+	inOther := &Item{ID: "2", Collection: "/api/collections/other", FinalVisibility: VisibilityPrivate}
+	created := func(obj *Item) Event {
+		return Event{Type: EventCreated, Kind: "item", IRI: obj.IRI(), Object: obj}
+	}
+	deleted := func(obj *Item) Event {
+		return Event{Type: EventDeleted, Kind: "item", IRI: obj.IRI()}
+	}
+
+	tests := []struct {
+		name      string
+		target    WebhookTarget
+		event     Event
+		delivered map[string]bool
+		want      bool
+	}{
+		{"no filters", WebhookTarget{}, created(inChild), nil, true},
+		{"kind", WebhookTarget{Kinds: []string{"collection"}}, created(inChild), nil, false},
+		{"type", WebhookTarget{Types: []EventType{EventUpdated}}, created(inChild), nil, false},
+		{"subtree", WebhookTarget{Collection: "/api/collections/root"}, created(inChild), nil, true},
+		{"outside subtree", WebhookTarget{Collection: "/api/collections/root"}, created(inOther), nil, false},
+		{"visibility", WebhookTarget{Visibilities: []Visibility{VisibilityPublic}}, created(inChild), nil, true},
+		{"other visibility", WebhookTarget{Visibilities: []Visibility{VisibilityPublic}}, created(inOther), nil, false},
+		{"deletion without filters", WebhookTarget{}, deleted(inOther), nil, true},
+		{"deletion never delivered", WebhookTarget{Collection: "/api/collections/root"}, deleted(inChild), map[string]bool{}, false},
+		{"deletion delivered before", WebhookTarget{Collection: "/api/collections/root"}, deleted(inChild), map[string]bool{inChild.IRI(): true}, true},
+	}
+	for _, tt := range tests {
+		if got := d.matches(&tt.target, tt.event, tt.delivered); got != tt.want {
+			t.Errorf("%s: matches = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWebhookDeliver(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !VerifyWebhook("s3cret", r.Header.Get(WebhookTimestampHeader), r.Header.Get(WebhookSignatureHeader), body) {
+			t.Errorf("bad signature %q", r.Header.Get(WebhookSignatureHeader))
+		}
+		if r.Header.Get(WebhookDeliveryHeader) != "abc" || r.Header.Get(WebhookEventHeader) != string(EventCreated) {
+			t.Errorf("headers = %v", r.Header)
+		}
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	deadLetters := filepath.Join(t.TempDir(), "dead.jsonl")
+	d := &dispatcher{opts: WebhookOptions{MaxAttempts: 3, Backoff: time.Millisecond, MaxBackoff: time.Millisecond, DeadLetterFile: deadLetters, HTTPClient: srv.Client()}}
+	target := &WebhookTarget{URL: srv.URL, Secret: "s3cret"}
+	p := WebhookPayload{ID: "abc", Type: EventCreated, Kind: "item", IRI: "/api/items/1"}
+
+	d.deliver(context.Background(), target, p)
+	if calls.Load() != 3 {
+		t.Fatalf("delivered in %d attempts, want 3", calls.Load())
+	}
+	if _, err := os.Stat(deadLetters); !os.IsNotExist(err) {
+		t.Error("a successful delivery was dead-lettered")
+	}
+
+	// The server now fails every request.
+	calls.Store(-100)
+	d.deliver(context.Background(), target, p)
+	f, err := os.Open(deadLetters)
+	if err != nil {
+		t.Fatalf("no dead letter: %v", err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		t.Fatal("empty dead-letter file")
+	}
+	var dl DeadLetter
+	if err := json.Unmarshal(scanner.Bytes(), &dl); err != nil {
+		t.Fatal(err)
+	}
+	var payload WebhookPayload
+	json.Unmarshal(dl.Payload, &payload)
+	if dl.Attempts != 3 || dl.URL != srv.URL || payload.ID != "abc" {
+		t.Errorf("dead letter = %+v", dl)
+	}
+}
+
+func TestEventID(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	e := Event{Type: EventUpdated, IRI: "/api/items/1", At: at}
+	if eventID(e) != eventID(Event{Type: EventUpdated, IRI: "/api/items/1", At: at.In(time.FixedZone("X", 3600))}) {
+		t.Error("event ID depends on the time zone")
+	}
+	if eventID(e) == eventID(Event{Type: EventUpdated, IRI: "/api/items/1", At: at.Add(time.Second)}) {
+		t.Error("event ID ignores the time")
+	}
+	if eventID(e) == eventID(Event{Type: EventDeleted, IRI: "/api/items/1", At: at}) {
+		t.Error("event ID ignores the type")
+	}
+}
@@ -7,26 +7,26 @@ import (
 
 // Wish represents a wish in Koillection, combining fields for JSON-LD and API interactions.
 type Wish struct {
-	Context             Context    `json:"@context,omitempty" access:"rw"`            // JSON-LD only
-	_ID                 ID         `json:"@id,omitempty" access:"ro"`                 // JSON-LD only
-	Type                string     `json:"@type,omitempty" access:"rw"`               // JSON-LD only
-	ID                  ID         `json:"id,omitempty" access:"ro"`                  // Identifier
-	Name                string     `json:"name" access:"rw"`                          // Wish name
-	URL                 string     `json:"url,omitempty" access:"rw"`                 // Wish URL
-	Price               string     `json:"price,omitempty" access:"rw"`               // Wish price
-	Currency            string     `json:"currency,omitempty" access:"rw"`            // Currency code
-	Wishlist            string     `json:"wishlist" access:"rw"`                      // Wishlist IRI
-	Owner               string     `json:"owner,omitempty" access:"ro"`               // Owner IRI
-	Comment             string     `json:"comment,omitempty" access:"rw"`             // Wish comment
-	Image               string     `json:"image,omitempty" access:"ro"`               // Image URL
-	ImageSmallThumbnail string     `json:"imageSmallThumbnail,omitempty" access:"ro"` // Small thumbnail URL
-	Visibility          Visibility `json:"visibility,omitempty" access:"rw"`          // Visibility level
-	ParentVisibility    string     `json:"parentVisibility,omitempty" access:"ro"`    // Parent visibility
-	FinalVisibility     Visibility `json:"finalVisibility,omitempty" access:"ro"`     // Effective visibility
-	ScrapedFromURL      string     `json:"scrapedFromUrl,omitempty" access:"ro"`      // Source URL
-	CreatedAt           time.Time  `json:"createdAt" access:"ro"`                     // Creation timestamp
-	UpdatedAt           time.Time  `json:"updatedAt,omitempty" access:"ro"`           // Update timestamp
-	File                string     `json:"file,omitempty" access:"wo"`                // Image file data
+	Context             Context             `json:"@context,omitempty" access:"rw"`                  // JSON-LD only
+	_ID                 ID                  `json:"@id,omitempty" access:"ro"`                       // JSON-LD only
+	Type                string              `json:"@type,omitempty" access:"rw"`                     // JSON-LD only
+	ID                  ID                  `json:"id,omitempty" access:"ro" render:"default"`       // Identifier
+	Name                string              `json:"name" access:"rw" render:"default"`               // Wish name
+	URL                 string              `json:"url,omitempty" access:"rw"`                       // Wish URL
+	Price               string              `json:"price,omitempty" access:"rw" render:"default"`    // Wish price
+	Currency            string              `json:"currency,omitempty" access:"rw" render:"default"` // Currency code
+	Wishlist            string              `json:"wishlist" access:"rw" render:"default"`           // Wishlist IRI
+	Owner               string              `json:"owner,omitempty" access:"ro"`                     // Owner IRI
+	Comment             string              `json:"comment,omitempty" access:"rw"`                   // Wish comment
+	Image               string              `json:"image,omitempty" access:"ro"`                     // Image URL
+	ImageSmallThumbnail string              `json:"imageSmallThumbnail,omitempty" access:"ro"`       // Small thumbnail URL
+	Visibility          Visibility          `json:"visibility,omitempty" access:"rw"`                // Visibility level
+	ParentVisibility    string              `json:"parentVisibility,omitempty" access:"ro"`          // Parent visibility
+	FinalVisibility     Visibility          `json:"finalVisibility,omitempty" access:"ro"`           // Effective visibility
+	ScrapedFromURL      string              `json:"scrapedFromUrl,omitempty" access:"ro"`            // Source URL
+	CreatedAt           time.Time           `json:"createdAt,omitzero" access:"ro"`                  // Creation timestamp
+	UpdatedAt           Nullable[time.Time] `json:"updatedAt,omitzero" access:"ro"`                  // Update timestamp
+	File                string              `json:"file,omitempty" access:"wo"`                      // Image file data
 
 }
 
@@ -1,6 +1,9 @@
 package koiApi
 
+// Client is satisfied by the live client and by CachedClient.
 type Client interface {
 	GetResponse() string
 	PrintError()
+	getResource(path string, out interface{}) error
+	listResources(path string, out interface{}, queryParams ...string) error
 }
@@ -1,20 +1,25 @@
 package koiApi
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"mime/multipart"
 	"net/http"
 	"net/http/cookiejar"
+	"net/textproto"
 	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -24,6 +29,7 @@ var (
 	ErrNotFound                  = errors.New("resource not found")
 	ErrUnprocessable             = errors.New("unprocessable entity")
 	ErrUnauthorized              = errors.New("unauthorized")
+	ErrTooLarge                  = errors.New("upload exceeds size limit")
 	globalContextTimeoutDuration = 30 * time.Second // Default timeout for context
 )
 
@@ -58,6 +64,9 @@ type koiClient struct {
 	lastResponse    *http.Response
 	koiError        *KoiError
 	rawError        string
+	user            *User      // Authenticated user, cached by me for feature checks; nil if it could not be determined
+	userAt          time.Time  // When user was last looked up; it is looked up again after userTTL
+	mu              sync.Mutex // Guards the fields above, which concurrent requests record into
 }
 
 // NewKoiClient creates a new HTTP client for the Koillection API.
@@ -86,6 +95,8 @@ func NewKoiClient(baseURL string, timeout time.Duration) *koiClient {
 
 // GetResponse retrieves the response from the httpClient struct.
 func (c *koiClient) GetResponse() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	if verbose {
 		fmt.Fprintln(os.Stderr, "--------------------------------------------------------")
 		// Print request headers
@@ -164,17 +175,17 @@ func (c *koiClient) GetResponse() string {
 
 // doRequest sends an HTTP request, stores it and the response in the httpClient struct, and returns the response.
 func (c *koiClient) doRequest(method, path string, body io.Reader, multipartContentType string) (*http.Response, error) {
+	return c.doRequestContext(context.Background(), method, path, body, multipartContentType)
+}
+
+// doRequestContext is doRequest bounded by ctx as well as the global timeout.
+func (c *koiClient) doRequestContext(ctx context.Context, method, path string, body io.Reader, multipartContentType string) (*http.Response, error) {
 	var bodyBytes []byte
 	if body != nil {
 		var err error
 		bodyBytes, err = io.ReadAll(body)
 		if err != nil {
-			c.lastRequest = nil
-			c.lastRequestBody = nil
-			c.lastResponse = nil
-			c.koiError = nil
-			c.rawError = fmt.Sprintf("Error reading request body: %v", err)
-			c.lastError = err
+			c.record(nil, nil, nil, err, fmt.Sprintf("Error reading request body: %v", err), nil)
 			return nil, fmt.Errorf("reading request body: %w", err)
 		}
 	}
@@ -185,16 +196,13 @@ func (c *koiClient) doRequest(method, path string, body io.Reader, multipartCont
 		reqBody = bytes.NewReader(bodyBytes)
 	}
 
-	ctx, _ := context.WithTimeout(context.Background(), globalContextTimeoutDuration)
+	// The response body is read in full below, so the context can be released on return.
+	ctx, cancel := context.WithTimeout(ctx, globalContextTimeoutDuration)
+	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
 	if err != nil {
-		c.lastRequest = nil
-		c.lastRequestBody = nil
-		c.lastResponse = nil
-		c.koiError = nil
-		c.rawError = fmt.Sprintf("Error creating request: %v", err)
-		c.lastError = err
+		c.record(nil, nil, nil, err, fmt.Sprintf("Error creating request: %v", err), nil)
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
 
@@ -213,42 +221,40 @@ func (c *koiClient) doRequest(method, path string, body io.Reader, multipartCont
 		req.Header.Set("Accept", "application/ld+json")
 	}
 
-	resp, err := c.httpClient.Do(req)
-	c.lastError = err
-	c.lastRequest = req
-	c.lastRequestBody = bodyBytes
-	s := fmt.Sprintf("%s%s", c.baseURL, path)
-	c.lastRequestURL = &s
-	c.lastResponse = resp
-	c.koiError = nil
-	c.rawError = ""
+	return c.send(c.httpClient, req, bodyBytes)
+}
 
+// send executes req with hc, records the request, response and any structured error in the client, and maps
+// the status code to an error. bodyBytes is the request body kept for error reporting; nil for streamed bodies.
+func (c *koiClient) send(hc *http.Client, req *http.Request, bodyBytes []byte) (*http.Response, error) {
+	resp, err := hc.Do(req)
 	if err != nil {
-		c.rawError = fmt.Sprintf("Request failed: %v", err)
+		c.record(req, bodyBytes, nil, err, fmt.Sprintf("Request failed: %v", err), nil)
 		return nil, fmt.Errorf("sending request: %w", err)
 	}
 
 	// Read the response body for all status codes.
-	respBodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		c.rawError = fmt.Sprintf("Error reading response body: %v", err)
+	var rawError string
+	respBodyBytes, readErr := io.ReadAll(resp.Body)
+	if readErr != nil {
+		rawError = fmt.Sprintf("Error reading response body: %v", readErr)
 	} else {
-		c.rawError = string(respBodyBytes)
+		rawError = string(respBodyBytes)
 	}
 	// Reset the response body so callers can read it.
 	resp.Body = io.NopCloser(bytes.NewReader(respBodyBytes))
 
 	// Handle 400 and 422 errors by attempting to unmarshal into KoiError.
+	var koiError *KoiError
 	if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnprocessableEntity {
-		if err == nil {
+		if readErr == nil {
 			var koiErr KoiError
-			if err := json.Unmarshal(respBodyBytes, &koiErr); err != nil {
-				c.koiError = nil // Explicitly set to nil if unmarshaling fails.
-			} else {
-				c.koiError = &koiErr
+			if err := json.Unmarshal(respBodyBytes, &koiErr); err == nil {
+				koiError = &koiErr
 			}
 		}
 	}
+	c.record(req, bodyBytes, resp, nil, rawError, koiError)
 
 	switch resp.StatusCode {
 	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
@@ -266,9 +272,31 @@ func (c *koiClient) doRequest(method, path string, body io.Reader, multipartCont
 	}
 }
 
+// record stores the outcome of the latest request for GetResponse and PrintError. A nil req keeps the
+// previous request URL.
+func (c *koiClient) record(req *http.Request, body []byte, resp *http.Response, err error, rawError string, koiError *KoiError) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastRequest = req
+	c.lastRequestBody = body
+	if req != nil {
+		s := req.URL.String()
+		c.lastRequestURL = &s
+	}
+	c.lastResponse = resp
+	c.lastError = err
+	c.koiError = koiError
+	c.rawError = rawError
+}
+
 // getResource retrieves a single resource and decodes it into the provided struct.
 func (c *koiClient) getResource(path string, out interface{}) error {
-	resp, err := c.doRequest(http.MethodGet, path, nil, "")
+	return c.getResourceContext(context.Background(), path, out)
+}
+
+// getResourceContext is getResource bounded by ctx.
+func (c *koiClient) getResourceContext(ctx context.Context, path string, out interface{}) error {
+	resp, err := c.doRequestContext(ctx, http.MethodGet, path, nil, "")
 	if err != nil {
 		return err
 	}
@@ -279,6 +307,11 @@ func (c *koiClient) getResource(path string, out interface{}) error {
 
 // listResources retrieves all resources by looping through all pages and decodes the member array.
 func (c *koiClient) listResources(path string, out interface{}, queryParams ...string) error {
+	return c.listResourcesContext(context.Background(), path, out, queryParams...)
+}
+
+// listResourcesContext is listResources bounded by ctx.
+func (c *koiClient) listResourcesContext(ctx context.Context, path string, out interface{}, queryParams ...string) error {
 	// Ensure out is a slice to collect all resources
 	outValue := reflect.ValueOf(out)
 	if outValue.Kind() != reflect.Ptr || outValue.Elem().Kind() != reflect.Slice {
@@ -315,7 +348,7 @@ func (c *koiClient) listResources(path string, out interface{}, queryParams ...s
 		u.RawQuery = encodedQuery
 
 		fmt.Printf("Fetching page %d: %s -- %s\n", page, u.String(), u.RawQuery)
-		resp, err := c.doRequest(http.MethodGet, u.Path+"?"+u.RawQuery, nil, "")
+		resp, err := c.doRequestContext(ctx, http.MethodGet, u.Path+"?"+u.RawQuery, nil, "")
 		if err != nil {
 			if verbose {
 				c.PrintError()
@@ -379,12 +412,17 @@ func (c *koiClient) listResources(path string, out interface{}, queryParams ...s
 
 // patchResource partially updates a resource and decodes the response into the provided struct.
 func (c *koiClient) patchResource(path string, in, out interface{}) error {
+	return c.patchResourceContext(context.Background(), path, in, out)
+}
+
+// patchResourceContext is patchResource bounded by ctx.
+func (c *koiClient) patchResourceContext(ctx context.Context, path string, in, out interface{}) error {
 	body, err := json.Marshal(in)
 	if err != nil {
 		return fmt.Errorf("encoding request body: %w", err)
 	}
 
-	resp, err := c.doRequest(http.MethodPatch, path, bytes.NewReader(body), "")
+	resp, err := c.doRequestContext(ctx, http.MethodPatch, path, bytes.NewReader(body), "")
 	if err != nil {
 		return err
 	}
@@ -395,7 +433,12 @@ func (c *koiClient) patchResource(path string, in, out interface{}) error {
 
 // deleteResource deletes a resource.
 func (c *koiClient) deleteResource(path string) error {
-	resp, err := c.doRequest(http.MethodDelete, path, nil, "")
+	return c.deleteResourceContext(context.Background(), path)
+}
+
+// deleteResourceContext is deleteResource bounded by ctx.
+func (c *koiClient) deleteResourceContext(ctx context.Context, path string) error {
+	resp, err := c.doRequestContext(ctx, http.MethodDelete, path, nil, "")
 	if err != nil {
 		return err
 	}
@@ -403,23 +446,86 @@ func (c *koiClient) deleteResource(path string) error {
 	return nil
 }
 
-// uploadFile uploads a file using multipart/form-data and decodes the response.
+// UploadOptions configures a streamed upload.
+type UploadOptions struct {
+	Filename    string                  // File name sent to the server; defaults to "upload" plus an extension for the content type
+	ContentType string                  // Part content type; detected from Filename or the content when empty
+	Size        int64                   // Size in bytes if known, used for the limit check and progress; 0 when unknown
+	MaxSize     int64                   // Reject uploads larger than this many bytes; 0 means no limit
+	User        *User                   // When set and MaxSize is 0, User.DiskSpaceAllowed is the limit
+	Progress    func(sent, total int64) // Called as data is sent; total is Size
+	Context     context.Context         // Cancels the upload, which has no timeout otherwise; defaults to context.Background()
+}
+
+// uploadFile uploads in-memory file data using multipart/form-data and decodes the response.
 func (c *koiClient) uploadFile(path string, file []byte, fieldName string, out interface{}) error {
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-	part, err := writer.CreateFormFile(fieldName, "upload")
-	if err != nil {
-		return fmt.Errorf("creating form file: %w", err)
+	return c.uploadStream(path, fieldName, bytes.NewReader(file), UploadOptions{Size: int64(len(file))}, out)
+}
+
+// uploadStream streams r to the server as a multipart/form-data part named fieldName without holding the whole
+// file in memory, and decodes the response. The client timeout is not applied, so large videos are not cut off;
+// opts.Context cancels a stalled upload instead.
+func (c *koiClient) uploadStream(path, fieldName string, r io.Reader, opts UploadOptions, out interface{}) error {
+	maxSize := opts.MaxSize
+	if maxSize == 0 && opts.User != nil && opts.User.DiskSpaceAllowed > 0 {
+		maxSize = int64(opts.User.DiskSpaceAllowed)
 	}
-	if _, err = part.Write(file); err != nil {
-		return fmt.Errorf("writing file: %w", err)
+	if maxSize > 0 && opts.Size > maxSize {
+		return fmt.Errorf("%d bytes: %w", opts.Size, ErrTooLarge)
 	}
-	if err := writer.Close(); err != nil {
-		return fmt.Errorf("closing writer: %w", err)
+
+	br := bufio.NewReaderSize(r, 512)
+	contentType := opts.ContentType
+	if contentType == "" && opts.Filename != "" {
+		contentType = mime.TypeByExtension(filepath.Ext(opts.Filename))
+	}
+	if contentType == "" {
+		head, _ := br.Peek(512)
+		contentType = http.DetectContentType(head)
+	}
+	filename := opts.Filename
+	if filename == "" {
+		filename = "upload"
+		if exts, _ := mime.ExtensionsByType(contentType); len(exts) > 0 {
+			filename += exts[0]
+		}
+	}
+
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	writer := multipart.NewWriter(pw)
+	go func() {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, quoteEscaper.Replace(fieldName), quoteEscaper.Replace(filename)))
+		header.Set("Content-Type", contentType)
+		part, err := writer.CreatePart(header)
+		if err == nil {
+			_, err = io.Copy(part, &progressReader{r: br, total: opts.Size, max: maxSize, progress: opts.Progress})
+		}
+		if err == nil {
+			err = writer.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	// The transport waits for the body to be written even after ctx is done, so the pipe is closed to unblock it.
+	stop := context.AfterFunc(ctx, func() { pr.CloseWithError(ctx.Err()) })
+	defer stop()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, pr)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
 	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Accept", "application/ld+json")
 
-	contentType := writer.FormDataContentType()
-	resp, err := c.doRequest(http.MethodPost, path, body, contentType)
+	// Uploads may take longer than the client timeout allows for ordinary requests.
+	hc := *c.httpClient
+	hc.Timeout = 0
+	resp, err := c.send(&hc, req, nil)
 	if err != nil {
 		return err
 	}
@@ -428,14 +534,49 @@ func (c *koiClient) uploadFile(path string, file []byte, fieldName string, out i
 	return json.NewDecoder(resp.Body).Decode(out)
 }
 
-// CheckLogin authenticates a user and returns a JWT token.
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// progressReader reports progress and enforces a size limit while a stream is read.
+type progressReader struct {
+	r        io.Reader
+	sent     int64
+	total    int64
+	max      int64
+	progress func(sent, total int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.sent += int64(n)
+	if p.max > 0 && p.sent > p.max {
+		return n, fmt.Errorf("more than %d bytes: %w", p.max, ErrTooLarge)
+	}
+	if p.progress != nil && n > 0 {
+		p.progress(p.sent, p.total)
+	}
+	return n, err
+}
+
+// getBytes retrieves the raw body of a resource such as an uploaded image or file. Relative URLs are
+// resolved against the client's server.
+func (c *koiClient) getBytes(path string) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := c.Download(context.Background(), path, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// CheckLogin authenticates the configured Auth user and returns a JWT token.
 func (c *koiClient) CheckLogin() (string, error) {
+	return c.Login(*Auth.Username, *Auth.Password)
+}
 
-	u := Auth.Username
-	p := Auth.Password
+// Login authenticates the given user and returns a JWT token.
+func (c *koiClient) Login(username, password string) (string, error) {
 	reqBody := map[string]string{
-		"username": *u,
-		"password": *p,
+		"username": username,
+		"password": password,
 	}
 	body, err := json.Marshal(reqBody)
 	if err != nil {
@@ -464,12 +605,17 @@ func (c *koiClient) CheckLogin() (string, error) {
 
 // postResource creates a resource and decodes the response into the provided struct.
 func (c *koiClient) postResource(path string, in, out interface{}) error {
+	return c.postResourceContext(context.Background(), path, in, out)
+}
+
+// postResourceContext is postResource bounded by ctx.
+func (c *koiClient) postResourceContext(ctx context.Context, path string, in, out interface{}) error {
 	body, err := json.Marshal(in)
 	if err != nil {
 		return fmt.Errorf("encoding request body: %w", err)
 	}
 
-	resp, err := c.doRequest(http.MethodPost, path, bytes.NewReader(body), "")
+	resp, err := c.doRequestContext(ctx, http.MethodPost, path, bytes.NewReader(body), "")
 	if err != nil {
 		return err
 	}
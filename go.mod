@@ -4,8 +4,10 @@ go 1.24.1
 
 require (
 	gitea.local/smalloy/caller-utils v0.0.0
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
 	github.com/stretchr/testify v1.10.0
 	golang.org/x/text v0.25.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 replace gitea.local/smalloy/caller-utils v0.0.0 => ../caller
@@ -13,5 +15,4 @@ replace gitea.local/smalloy/caller-utils v0.0.0 => ../caller
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
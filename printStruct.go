@@ -206,8 +206,8 @@ func printStruct(v interface{}, indentLevel int, verbose bool, format string, ar
 						continue
 					}
 				case reflect.Struct:
-					if field.Type() == reflect.TypeOf(time.Time{}) && field.Interface().(time.Time).IsZero() {
-						continue
+					if z, ok := field.Interface().(interface{ IsZero() bool }); ok && z.IsZero() {
+						continue // time.Time, Nullable
 					}
 				}
 			}
@@ -293,8 +293,8 @@ func printStruct(v interface{}, indentLevel int, verbose bool, format string, ar
 			case reflect.Bool:
 				fmt.Printf("%s:%s%t\n", prefix, padding, field.Bool())
 			case reflect.Struct:
-				if field.Type() == reflect.TypeOf(time.Time{}) {
-					fmt.Printf("%s:%s%v\n", prefix, padding, field.Interface())
+				if _, ok := field.Interface().(fmt.Stringer); ok {
+					fmt.Printf("%s:%s%v\n", prefix, padding, field.Interface()) // time.Time, Nullable
 				} else {
 					fmt.Printf("%s:%s%v\n", prefix, padding, field.String())
 				}
@@ -0,0 +1,372 @@
+// Package render writes koiApi objects, or slices of them, to an io.Writer as an aligned table, JSON, JSON
+// Lines, YAML, Markdown or CSV.
+//
+// Columns are named by their JSON names. Without an explicit selection, the fields tagged render:"default"
+// are shown; types without such tags show their scalar fields. A field tagged render:"-" is never shown.
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is an output format.
+type Format string
+
+const (
+	Table    Format = "table"
+	JSON     Format = "json"
+	JSONL    Format = "jsonl"
+	YAML     Format = "yaml"
+	Markdown Format = "markdown"
+	CSV      Format = "csv"
+)
+
+// Formats lists the supported formats.
+var Formats = []Format{Table, JSON, JSONL, YAML, Markdown, CSV}
+
+// ParseFormat returns the format with the given name, accepting md for Markdown and ndjson for JSON Lines.
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case "", Table:
+		return Table, nil
+	case "md":
+		return Markdown, nil
+	case "ndjson":
+		return JSONL, nil
+	case JSON, JSONL, YAML, Markdown, CSV:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown format %q; expected one of %v", name, Formats)
+}
+
+// Options configures Write.
+type Options struct {
+	Format     Format   // Defaults to Table
+	Columns    []string // JSON or Go field names to show, in order; empty shows the default columns
+	AllColumns bool     // With no Columns, show every field rather than the default columns
+	NoHeader   bool     // Leave out the header row of tables, Markdown and CSV
+	NoColor    bool     // Plain table output; colour is also off when NO_COLOR is set or w is not a terminal
+	Width      int      // Maximum table width; 0 uses $COLUMNS when writing to a terminal, negative means unlimited
+}
+
+// Write renders v, a struct, a pointer to one, or a slice or array of either, to w.
+func Write(w io.Writer, v any, opts Options) error {
+	if opts.Format == "" {
+		opts.Format = Table
+	}
+	records, typ, err := recordsOf(v)
+	if err != nil {
+		return err
+	}
+	// JSON and YAML keep whole objects unless columns were chosen.
+	if len(opts.Columns) == 0 && (opts.Format == JSON || opts.Format == JSONL || opts.Format == YAML) {
+		return writeObjects(w, v, records, opts.Format)
+	}
+	cols, err := columns(typ, opts.Columns, opts.AllColumns)
+	if err != nil {
+		return err
+	}
+	switch opts.Format {
+	case Table:
+		return writeTable(w, records, cols, opts)
+	case Markdown:
+		return writeMarkdown(w, records, cols, opts)
+	case CSV:
+		return writeCSV(w, records, cols, opts)
+	case JSON, JSONL, YAML:
+		if !isList(v) && len(records) == 0 {
+			return writeObjects(w, nil, nil, opts.Format) // A nil pointer renders as null
+		}
+		objs := make([]json.RawMessage, len(records))
+		for i, r := range records {
+			if objs[i], err = selectedJSON(r, cols); err != nil {
+				return err
+			}
+		}
+		if isList(v) || opts.Format == JSONL {
+			return writeObjects(w, objs, nil, opts.Format)
+		}
+		return writeObjects(w, objs[0], nil, opts.Format)
+	}
+	return fmt.Errorf("unknown format %q", opts.Format)
+}
+
+// recordsOf returns the struct values in v and their type.
+func recordsOf(v any) ([]reflect.Value, reflect.Type, error) {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return nil, nil, fmt.Errorf("nothing to render")
+	}
+	var records []reflect.Value
+	var typ reflect.Type
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		typ = rv.Type().Elem()
+		for i := 0; i < rv.Len(); i++ {
+			if e := deref(rv.Index(i)); e.IsValid() {
+				records = append(records, e)
+			}
+		}
+	} else {
+		typ = rv.Type()
+		if e := deref(rv); e.IsValid() {
+			records = append(records, e)
+		}
+	}
+	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Interface {
+		if typ.Kind() == reflect.Interface {
+			if len(records) == 0 {
+				return nil, nil, fmt.Errorf("cannot render an empty %s", rv.Type())
+			}
+			typ = records[0].Type()
+			break
+		}
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("cannot render %s: not a struct", typ)
+	}
+	return records, typ, nil
+}
+
+func deref(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func isList(v any) bool {
+	t := reflect.TypeOf(v)
+	return t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array)
+}
+
+// column is a field shown in tabular output.
+type column struct {
+	name  string // JSON name
+	index []int  // Field index path, through embedded structs
+}
+
+// columns resolves the selected column names against typ, or picks the default columns.
+func columns(typ reflect.Type, names []string, all bool) ([]column, error) {
+	fields := fieldsOf(typ, nil)
+	if len(names) > 0 {
+		cols := make([]column, 0, len(names))
+		for _, name := range names {
+			i := indexOf(fields, name)
+			if i < 0 {
+				return nil, fmt.Errorf("%s has no column %q", typ.Name(), name)
+			}
+			cols = append(cols, fields[i].column)
+		}
+		return cols, nil
+	}
+	var tagged, scalar, every []column
+	for _, f := range fields {
+		if f.hidden {
+			continue
+		}
+		every = append(every, f.column)
+		if f.isDefault {
+			tagged = append(tagged, f.column)
+		}
+		if f.scalar {
+			scalar = append(scalar, f.column)
+		}
+	}
+	switch {
+	case all:
+		return every, nil
+	case len(tagged) > 0:
+		return tagged, nil
+	}
+	return scalar, nil
+}
+
+type field struct {
+	column
+	goName    string
+	isDefault bool // Tagged render:"default"
+	hidden    bool // Tagged render:"-", write-only or JSON-LD
+	scalar    bool // Renders as a short cell
+}
+
+// fieldsOf lists the exported fields of typ in order, flattening embedded structs.
+func fieldsOf(typ reflect.Type, index []int) []field {
+	var fields []field
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		idx := append(append([]int(nil), index...), i)
+		if sf.Anonymous {
+			t := sf.Type
+			if t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			if t.Kind() == reflect.Struct && sf.Tag.Get("json") == "" {
+				fields = append(fields, fieldsOf(t, idx)...)
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		name := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		tag := sf.Tag.Get("render")
+		fields = append(fields, field{
+			column:    column{name: name, index: idx},
+			goName:    sf.Name,
+			isDefault: tag == "default",
+			hidden:    tag == "-" || sf.Tag.Get("access") == "wo" || strings.HasPrefix(name, "@"),
+			scalar:    isScalar(sf.Type),
+		})
+	}
+	return fields
+}
+
+func indexOf(fields []field, name string) int {
+	for i, f := range fields {
+		if strings.EqualFold(f.name, name) || strings.EqualFold(f.goName, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isScalar(t reflect.Type) bool {
+	if t.Implements(stringerType) || reflect.PointerTo(t).Implements(stringerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Ptr:
+		return isScalar(t.Elem())
+	}
+	return false
+}
+
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+// fieldValue returns the value of c in record, or an invalid value if a nil embedded pointer is in the way.
+func fieldValue(record reflect.Value, c column) reflect.Value {
+	v := record
+	for _, i := range c.index {
+		v = deref(v)
+		if !v.IsValid() {
+			return v
+		}
+		v = v.Field(i)
+	}
+	return v
+}
+
+// selectedJSON encodes the chosen columns of record as a JSON object, keeping their order.
+func selectedJSON(record reflect.Value, cols []column) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, c := range cols {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(c.name)
+		buf.Write(key)
+		buf.WriteByte(':')
+		var val any
+		if v := fieldValue(record, c); v.IsValid() {
+			val = v.Interface()
+		}
+		data, err := json.Marshal(val)
+		if err != nil {
+			return nil, fmt.Errorf("encoding %s: %w", c.name, err)
+		}
+		buf.Write(data)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// writeObjects writes v as indented JSON, one JSON document per record, or YAML.
+func writeObjects(w io.Writer, v any, records []reflect.Value, format Format) error {
+	switch format {
+	case JSONL:
+		enc := json.NewEncoder(w)
+		if raws, ok := v.([]json.RawMessage); ok {
+			for _, r := range raws {
+				if err := enc.Encode(r); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		if !isList(v) {
+			return enc.Encode(v)
+		}
+		for _, r := range records {
+			// Array elements are not addressable; a pointer to a copy keeps pointer-receiver marshalers working.
+			p := reflect.New(r.Type())
+			p.Elem().Set(r)
+			if err := enc.Encode(p.Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case YAML:
+		// Going through JSON keeps the JSON names, field order and omitempty rules of the models.
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return err
+		}
+		blockStyle(&node)
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(&node); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// blockStyle turns the flow style that JSON input parses into back into YAML's block style.
+func blockStyle(n *yaml.Node) {
+	n.Style &^= yaml.FlowStyle
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!str" {
+		n.Style &^= yaml.DoubleQuotedStyle
+	}
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
+
+// isTerminal reports whether w is a character device such as a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
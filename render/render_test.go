@@ -0,0 +1,181 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	koi "gitea.local/smalloy/koiApi"
+)
+
+func testTags() []*koi.Tag {
+	return []*koi.Tag{
+		{ID: "1", Label: "signed", Visibility: koi.VisibilityPublic, Description: "Signed by the author"},
+		{ID: "2", Label: "first | edition", Category: "/api/tag_categories/9", File: "secret"},
+	}
+}
+
+func render(t *testing.T, v any, opts Options) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Write(&buf, v, opts); err != nil {
+		t.Fatalf("Write(%T, %+v): %v", v, opts, err)
+	}
+	return buf.String()
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := map[string]Format{"": Table, "TABLE": Table, "json": JSON, "ndjson": JSONL, "jsonl": JSONL, "yaml": YAML, "md": Markdown, "markdown": Markdown, "csv": CSV}
+	for name, want := range tests {
+		if got, err := ParseFormat(name); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat(xml) succeeded")
+	}
+}
+
+func TestWriteFormats(t *testing.T) {
+	cols := []string{"id", "Label", "visibility"}
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{Table, "ID  LABEL            VISIBILITY\n1   signed           public\n2   first | edition\n"},
+		{CSV, "id,label,visibility\n1,signed,public\n2,first | edition,\n"},
+		{Markdown, "| id | label | visibility |\n| --- | --- | --- |\n| 1 | signed | public |\n| 2 | first \\| edition |  |\n"},
+		{JSON, "[\n  {\n    \"id\": \"1\",\n    \"label\": \"signed\",\n    \"visibility\": \"public\"\n  },\n  {\n    \"id\": \"2\",\n    \"label\": \"first | edition\",\n    \"visibility\": \"\"\n  }\n]\n"},
+		{JSONL, "{\"id\":\"1\",\"label\":\"signed\",\"visibility\":\"public\"}\n{\"id\":\"2\",\"label\":\"first | edition\",\"visibility\":\"\"}\n"},
+		{YAML, "- id: \"1\"\n  label: signed\n  visibility: public\n- id: \"2\"\n  label: first | edition\n  visibility: \"\"\n"},
+	}
+	for _, tt := range tests {
+		got := render(t, testTags(), Options{Format: tt.format, Columns: cols, Width: -1})
+		if got != tt.want {
+			t.Errorf("%s:\n%s\nwant:\n%s", tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestWriteWholeObjects(t *testing.T) {
+	got := render(t, testTags(), Options{Format: JSONL})
+	lines := strings.Split(strings.TrimSpace(got), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"description":"Signed by the author"`) {
+		t.Errorf("JSONL = %s", got)
+	}
+	got = render(t, testTags()[0], Options{Format: YAML})
+	if !strings.Contains(got, "label: signed\n") || strings.Contains(got, "{") {
+		t.Errorf("YAML = %s", got)
+	}
+}
+
+func TestWriteArray(t *testing.T) {
+	arr := [2]koi.Tag{{Label: "x"}, {Label: "y"}}
+	for _, opts := range []Options{{Format: JSONL}, {Format: JSONL, Columns: []string{"label"}}, {Format: CSV, Columns: []string{"label"}}} {
+		got := render(t, arr, opts)
+		if !strings.Contains(got, "x") || !strings.Contains(got, "y") || strings.Count(got, "\n") < 2 {
+			t.Errorf("%+v: %q", opts, got)
+		}
+	}
+}
+
+func TestWriteSingleObjectWithColumns(t *testing.T) {
+	tag := testTags()[0]
+	tests := map[Format]string{
+		JSON:  "{\n  \"label\": \"signed\"\n}\n",
+		JSONL: "{\"label\":\"signed\"}\n",
+		YAML:  "label: signed\n",
+	}
+	for format, want := range tests {
+		if got := render(t, tag, Options{Format: format, Columns: []string{"label"}}); got != want {
+			t.Errorf("%s = %q, want %q", format, got, want)
+		}
+	}
+}
+
+func TestWriteNil(t *testing.T) {
+	for _, format := range []Format{JSON, JSONL, YAML} {
+		for _, cols := range [][]string{nil, {"label"}} {
+			if got := render(t, (*koi.Tag)(nil), Options{Format: format, Columns: cols}); got != "null\n" {
+				t.Errorf("%s with columns %v = %q, want null", format, cols, got)
+			}
+		}
+	}
+	if got := render(t, (*koi.Tag)(nil), Options{Format: CSV, Columns: []string{"label"}}); got != "label\n" {
+		t.Errorf("CSV = %q", got)
+	}
+	if err := Write(&bytes.Buffer{}, nil, Options{}); err == nil {
+		t.Error("Write(nil) succeeded")
+	}
+}
+
+func TestColumns(t *testing.T) {
+	got := render(t, testTags(), Options{Format: CSV})
+	if header := strings.SplitN(got, "\n", 2)[0]; header != "id,label,category,visibility" {
+		t.Errorf("default columns = %s", header)
+	}
+	all := strings.SplitN(render(t, testTags(), Options{Format: CSV, AllColumns: true}), "\n", 2)[0]
+	if !strings.Contains(all, "description") || strings.Contains(all, "file") || strings.Contains(all, "@") {
+		t.Errorf("all columns = %s", all)
+	}
+	if err := Write(&bytes.Buffer{}, testTags(), Options{Columns: []string{"nope"}}); err == nil {
+		t.Error("unknown column accepted")
+	}
+
+	// Types without render tags show their scalar fields.
+	type plain struct {
+		Name  string   `json:"name"`
+		Count int      `json:"count"`
+		List  []string `json:"list"`
+	}
+	if got := render(t, plain{Name: "a", Count: 2, List: []string{"x"}}, Options{Format: CSV}); got != "name,count\na,2\n" {
+		t.Errorf("scalar columns = %q", got)
+	}
+}
+
+func TestCells(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	type row struct {
+		Set     koi.Nullable[time.Time] `json:"set"`
+		Null    koi.Nullable[string]    `json:"null"`
+		At      time.Time               `json:"at"`
+		Zero    time.Time               `json:"zero"`
+		List    []string                `json:"list"`
+		Ptr     *int                    `json:"ptr"`
+		Float   float64                 `json:"float"`
+		Nested  map[string]int          `json:"nested"`
+		Message string                  `json:"message"`
+	}
+	r := row{Set: koi.NewNullable(at), Null: koi.Null[string](), At: at, List: []string{"a", "b"}, Float: 1.5, Nested: map[string]int{"k": 1}, Message: "two\nlines"}
+	got := render(t, r, Options{Format: CSV, AllColumns: true, NoHeader: true})
+	want := "2024-03-01 12:30:00,,2024-03-01 12:30:00,,\"a, b\",,1.5,\"{\"\"k\"\":1}\",\"two\nlines\"\n"
+	if got != want {
+		t.Errorf("cells = %q, want %q", got, want)
+	}
+	if got := render(t, r, Options{Format: Markdown, Columns: []string{"message"}, NoHeader: true}); got != "| two<br>lines |\n" {
+		t.Errorf("markdown cell = %q", got)
+	}
+}
+
+func TestFitWidths(t *testing.T) {
+	widths := []int{2, 30, 10}
+	fitWidths(widths, 30)
+	if sum := widths[0] + widths[1] + widths[2] + 4; sum > 30 || widths[0] != 2 {
+		t.Errorf("widths = %v", widths)
+	}
+	widths = []int{20, 20}
+	fitWidths(widths, 5)
+	if widths[0] != minColumnWidth || widths[1] != minColumnWidth {
+		t.Errorf("widths below the minimum: %v", widths)
+	}
+	if got := truncate("héllo world", 5); got != "héll…" {
+		t.Errorf("truncate = %q", got)
+	}
+	got := render(t, testTags(), Options{Columns: []string{"label", "description"}, Width: 20, NoHeader: true})
+	for _, line := range strings.Split(strings.TrimSuffix(got, "\n"), "\n") {
+		if n := len([]rune(line)); n > 20 {
+			t.Errorf("line %q is %d wide", line, n)
+		}
+	}
+}
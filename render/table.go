@@ -0,0 +1,252 @@
+package render
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ansiBold  = "\x1b[1m"
+	ansiDim   = "\x1b[2m"
+	ansiReset = "\x1b[0m"
+)
+
+// minColumnWidth is the narrowest a column is shrunk to when fitting a table to the width.
+const minColumnWidth = 6
+
+func writeTable(w io.Writer, records []reflect.Value, cols []column, opts Options) error {
+	color := !opts.NoColor && os.Getenv("NO_COLOR") == "" && isTerminal(w)
+	rows := cells(records, cols)
+	widths := make([]int, len(cols))
+	if !opts.NoHeader {
+		for i, c := range cols {
+			widths[i] = len([]rune(c.name))
+		}
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len([]rune(cell)))
+		}
+	}
+	fitWidths(widths, tableWidth(w, opts.Width))
+
+	line := func(row []string, style string) error {
+		var sb strings.Builder
+		for i, cell := range row {
+			if i > 0 {
+				sb.WriteString("  ")
+			}
+			cell = truncate(cell, widths[i])
+			if i == len(row)-1 {
+				sb.WriteString(cell) // No trailing padding
+			} else {
+				sb.WriteString(cell + strings.Repeat(" ", widths[i]-len([]rune(cell))))
+			}
+		}
+		text := strings.TrimRight(sb.String(), " ") // An empty last cell leaves the separator behind
+		if style != "" {
+			text = style + text + ansiReset
+		}
+		_, err := fmt.Fprintln(w, text)
+		return err
+	}
+	if !opts.NoHeader {
+		header := make([]string, len(cols))
+		for i, c := range cols {
+			header[i] = strings.ToUpper(c.name)
+		}
+		style := ""
+		if color {
+			style = ansiBold
+		}
+		if err := line(header, style); err != nil {
+			return err
+		}
+	}
+	for i, row := range rows {
+		style := ""
+		if color && i%2 == 1 {
+			style = ansiDim
+		}
+		if err := line(row, style); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tableWidth returns the width limit for tables written to w, or 0 for none.
+func tableWidth(w io.Writer, width int) int {
+	if width != 0 {
+		return max(width, 0)
+	}
+	if !isTerminal(w) {
+		return 0
+	}
+	n, _ := strconv.Atoi(os.Getenv("COLUMNS"))
+	return n
+}
+
+// fitWidths shrinks the widest columns until the table, with two spaces between columns, fits in limit.
+func fitWidths(widths []int, limit int) {
+	if limit <= 0 {
+		return
+	}
+	total := func() int {
+		sum := 2 * (len(widths) - 1)
+		for _, w := range widths {
+			sum += w
+		}
+		return sum
+	}
+	for total() > limit {
+		widest := 0
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minColumnWidth {
+			return
+		}
+		widths[widest]--
+	}
+}
+
+func truncate(s string, width int) string {
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	if width <= 1 {
+		return string(r[:width])
+	}
+	return string(r[:width-1]) + "…"
+}
+
+func writeMarkdown(w io.Writer, records []reflect.Value, cols []column, opts Options) error {
+	escape := strings.NewReplacer("|", `\|`, "\n", "<br>", "\r", "")
+	row := func(cells []string) error {
+		for i := range cells {
+			cells[i] = escape.Replace(cells[i])
+		}
+		_, err := fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+		return err
+	}
+	if !opts.NoHeader {
+		header := make([]string, len(cols))
+		rule := make([]string, len(cols))
+		for i, c := range cols {
+			header[i] = c.name
+			rule[i] = "---"
+		}
+		if err := row(header); err != nil {
+			return err
+		}
+		if err := row(rule); err != nil {
+			return err
+		}
+	}
+	for _, cells := range cells(records, cols) {
+		if err := row(cells); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeCSV(w io.Writer, records []reflect.Value, cols []column, opts Options) error {
+	cw := csv.NewWriter(w)
+	if !opts.NoHeader {
+		header := make([]string, len(cols))
+		for i, c := range cols {
+			header[i] = c.name
+		}
+		cw.Write(header)
+	}
+	for _, row := range cells(records, cols) {
+		cw.Write(row)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// cells formats the columns of each record.
+func cells(records []reflect.Value, cols []column) [][]string {
+	rows := make([][]string, len(records))
+	for r, record := range records {
+		rows[r] = make([]string, len(cols))
+		for i, c := range cols {
+			rows[r][i] = cell(fieldValue(record, c))
+		}
+	}
+	return rows
+}
+
+// cell formats one value: times as date and time, nullable values by their content, lists comma separated
+// and anything else structured as JSON.
+func cell(v reflect.Value) string {
+	if !v.IsValid() {
+		return ""
+	}
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		return cell(v.Elem())
+	}
+	if v.CanInterface() {
+		switch x := v.Interface().(type) {
+		case time.Time:
+			if x.IsZero() {
+				return ""
+			}
+			return x.Format(time.DateTime)
+		case interface{ IsZero() bool }:
+			if x.IsZero() {
+				return ""
+			}
+		}
+		// Nullable values expose Get() (T, bool).
+		if get := v.MethodByName("Get"); get.IsValid() && get.Type().NumIn() == 0 && get.Type().NumOut() == 2 && get.Type().Out(1).Kind() == reflect.Bool {
+			out := get.Call(nil)
+			if !out[1].Bool() {
+				return ""
+			}
+			return cell(out[0])
+		}
+		if s, ok := v.Interface().(fmt.Stringer); ok {
+			return s.String()
+		}
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Slice, reflect.Array:
+		parts := make([]string, v.Len())
+		for i := range parts {
+			parts[i] = cell(v.Index(i))
+		}
+		return strings.Join(parts, ", ")
+	}
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return fmt.Sprint(v.Interface())
+	}
+	return string(data)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	caller "gitea.local/smalloy/caller-utils"
 )
@@ -54,3 +55,46 @@ func indentChars(num int, args ...string) string {
 func lastChars(s string, n int) string {
 	return s[len(s)-n:]
 }
+
+// idFromIRI returns the trailing identifier of an IRI such as /api/items/{id}
+func idFromIRI(iri string) ID {
+	return ID(iri[strings.LastIndex(iri, "/")+1:])
+}
+
+// writableCopy returns a copy of obj holding only the fields a client may send (access "rw" or "wo"),
+// leaving out identifiers, JSON-LD keywords and read-only server fields.
+func writableCopy[T KoiObject](obj T) T {
+	src := reflect.ValueOf(obj).Elem()
+	dst := reflect.New(src.Type())
+	for i := 0; i < src.NumField(); i++ {
+		field := src.Type().Field(i)
+		if !field.IsExported() || strings.HasPrefix(field.Tag.Get("json"), "@") {
+			continue
+		}
+		switch field.Tag.Get("access") {
+		case "rw", "wo":
+			dst.Elem().Field(i).Set(src.Field(i))
+		}
+	}
+	return dst.Interface().(T)
+}
+
+// modifiedAt returns obj's UpdatedAt, or its CreatedAt when it has never been updated.
+func modifiedAt(obj KoiObject) time.Time {
+	v := reflect.ValueOf(obj).Elem()
+	for _, name := range []string{"UpdatedAt", "CreatedAt"} {
+		if f := v.FieldByName(name); f.IsValid() {
+			switch t := f.Interface().(type) {
+			case time.Time:
+				if !t.IsZero() {
+					return t
+				}
+			case Nullable[time.Time]:
+				if !t.OrZero().IsZero() {
+					return t.OrZero()
+				}
+			}
+		}
+	}
+	return time.Time{}
+}